@@ -0,0 +1,15 @@
+// Package example shows the wrappers generated by streamgen.
+package example
+
+//go:generate go run github.com/vkumbhar94/go-streams/cmd/streamgen -types Order,Item,string
+
+type Order struct {
+	ID    int
+	Paid  bool
+	Items []Item
+}
+
+type Item struct {
+	SKU      string
+	Quantity int
+}
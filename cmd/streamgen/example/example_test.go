@@ -0,0 +1,41 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vkumbhar94/go-streams/pkg/streams"
+)
+
+func TestGeneratedStreams(t *testing.T) {
+	orders := []Order{
+		{ID: 1, Paid: true, Items: []Item{{"apple", 2}, {"pear", 1}}},
+		{ID: 2, Paid: false, Items: []Item{{"fig", 5}}},
+		{ID: 3, Paid: true, Items: []Item{{"kiwi", 3}}},
+	}
+	skus := NewOrderStream(streams.FromSlice(orders)).
+		Filter(func(o Order) bool {
+			return o.Paid
+		}).
+		FlatMapToItem(func(o Order) *streams.Stream[Item] {
+			return streams.FromSlice(o.Items)
+		}).
+		Filter(func(i Item) bool {
+			return i.Quantity > 1
+		}).
+		MapToString(func(i Item) string {
+			return i.SKU
+		}).
+		Collect()
+	assert.Equal(t, []string{"apple", "kiwi"}, skus)
+
+	initials := NewOrderStream(streams.FromSlice(orders)).
+		FlatMapToItem(func(o Order) *streams.Stream[Item] {
+			return streams.FromSlice(o.Items)
+		}).
+		ReduceToString("", func(acc string, i Item) string {
+			return acc + i.SKU[:1]
+		})
+	assert.Equal(t, "apfk", initials)
+}
@@ -0,0 +1,205 @@
+// Code generated by streamgen; DO NOT EDIT.
+
+package example
+
+import (
+	"github.com/vkumbhar94/go-streams/pkg/streams"
+)
+
+// OrderStream is a stream of Order with its type changing operations as
+// methods.
+type OrderStream struct {
+	*streams.Stream[Order]
+}
+
+func NewOrderStream(s *streams.Stream[Order]) *OrderStream {
+	return &OrderStream{s}
+}
+
+func (s *OrderStream) Filter(f streams.FilterFun[Order]) *OrderStream {
+	return &OrderStream{s.Stream.Filter(f)}
+}
+
+func (s *OrderStream) Map(mapper streams.UnaryMapFun[Order]) *OrderStream {
+	return &OrderStream{s.Stream.Map(mapper)}
+}
+
+func (s *OrderStream) Peek(f func(Order)) *OrderStream {
+	return &OrderStream{s.Stream.Peek(f)}
+}
+
+func (s *OrderStream) Limit(n int) *OrderStream {
+	return &OrderStream{s.Stream.Limit(n)}
+}
+
+func (s *OrderStream) Skip(n int) *OrderStream {
+	return &OrderStream{s.Stream.Skip(n)}
+}
+
+func (s *OrderStream) DropWhile(f func(Order) bool) *OrderStream {
+	return &OrderStream{s.Stream.DropWhile(f)}
+}
+
+func (s *OrderStream) TakeWhile(f func(Order) bool) *OrderStream {
+	return &OrderStream{s.Stream.TakeWhile(f)}
+}
+
+func (s *OrderStream) Reverse() *OrderStream {
+	return &OrderStream{s.Stream.Reverse()}
+}
+
+func (s *OrderStream) MapToItem(mapper func(Order) Item) *ItemStream {
+	return &ItemStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *OrderStream) FlatMapToItem(f func(Order) *streams.Stream[Item]) *ItemStream {
+	return &ItemStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *OrderStream) ReduceToItem(init Item, f func(Item, Order) Item) Item {
+	return streams.Reduce(s.Stream, init, f)
+}
+
+func (s *OrderStream) MapToString(mapper func(Order) string) *StringStream {
+	return &StringStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *OrderStream) FlatMapToString(f func(Order) *streams.Stream[string]) *StringStream {
+	return &StringStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *OrderStream) ReduceToString(init string, f func(string, Order) string) string {
+	return streams.Reduce(s.Stream, init, f)
+}
+
+// ItemStream is a stream of Item with its type changing operations as
+// methods.
+type ItemStream struct {
+	*streams.Stream[Item]
+}
+
+func NewItemStream(s *streams.Stream[Item]) *ItemStream {
+	return &ItemStream{s}
+}
+
+func (s *ItemStream) Filter(f streams.FilterFun[Item]) *ItemStream {
+	return &ItemStream{s.Stream.Filter(f)}
+}
+
+func (s *ItemStream) Map(mapper streams.UnaryMapFun[Item]) *ItemStream {
+	return &ItemStream{s.Stream.Map(mapper)}
+}
+
+func (s *ItemStream) Peek(f func(Item)) *ItemStream {
+	return &ItemStream{s.Stream.Peek(f)}
+}
+
+func (s *ItemStream) Limit(n int) *ItemStream {
+	return &ItemStream{s.Stream.Limit(n)}
+}
+
+func (s *ItemStream) Skip(n int) *ItemStream {
+	return &ItemStream{s.Stream.Skip(n)}
+}
+
+func (s *ItemStream) DropWhile(f func(Item) bool) *ItemStream {
+	return &ItemStream{s.Stream.DropWhile(f)}
+}
+
+func (s *ItemStream) TakeWhile(f func(Item) bool) *ItemStream {
+	return &ItemStream{s.Stream.TakeWhile(f)}
+}
+
+func (s *ItemStream) Reverse() *ItemStream {
+	return &ItemStream{s.Stream.Reverse()}
+}
+
+func (s *ItemStream) MapToOrder(mapper func(Item) Order) *OrderStream {
+	return &OrderStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *ItemStream) FlatMapToOrder(f func(Item) *streams.Stream[Order]) *OrderStream {
+	return &OrderStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *ItemStream) ReduceToOrder(init Order, f func(Order, Item) Order) Order {
+	return streams.Reduce(s.Stream, init, f)
+}
+
+func (s *ItemStream) MapToString(mapper func(Item) string) *StringStream {
+	return &StringStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *ItemStream) FlatMapToString(f func(Item) *streams.Stream[string]) *StringStream {
+	return &StringStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *ItemStream) ReduceToString(init string, f func(string, Item) string) string {
+	return streams.Reduce(s.Stream, init, f)
+}
+
+// StringStream is a stream of string with its type changing operations as
+// methods.
+type StringStream struct {
+	*streams.Stream[string]
+}
+
+func NewStringStream(s *streams.Stream[string]) *StringStream {
+	return &StringStream{s}
+}
+
+func (s *StringStream) Filter(f streams.FilterFun[string]) *StringStream {
+	return &StringStream{s.Stream.Filter(f)}
+}
+
+func (s *StringStream) Map(mapper streams.UnaryMapFun[string]) *StringStream {
+	return &StringStream{s.Stream.Map(mapper)}
+}
+
+func (s *StringStream) Peek(f func(string)) *StringStream {
+	return &StringStream{s.Stream.Peek(f)}
+}
+
+func (s *StringStream) Limit(n int) *StringStream {
+	return &StringStream{s.Stream.Limit(n)}
+}
+
+func (s *StringStream) Skip(n int) *StringStream {
+	return &StringStream{s.Stream.Skip(n)}
+}
+
+func (s *StringStream) DropWhile(f func(string) bool) *StringStream {
+	return &StringStream{s.Stream.DropWhile(f)}
+}
+
+func (s *StringStream) TakeWhile(f func(string) bool) *StringStream {
+	return &StringStream{s.Stream.TakeWhile(f)}
+}
+
+func (s *StringStream) Reverse() *StringStream {
+	return &StringStream{s.Stream.Reverse()}
+}
+
+func (s *StringStream) MapToOrder(mapper func(string) Order) *OrderStream {
+	return &OrderStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *StringStream) FlatMapToOrder(f func(string) *streams.Stream[Order]) *OrderStream {
+	return &OrderStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *StringStream) ReduceToOrder(init Order, f func(Order, string) Order) Order {
+	return streams.Reduce(s.Stream, init, f)
+}
+
+func (s *StringStream) MapToItem(mapper func(string) Item) *ItemStream {
+	return &ItemStream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *StringStream) FlatMapToItem(f func(string) *streams.Stream[Item]) *ItemStream {
+	return &ItemStream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *StringStream) ReduceToItem(init Item, f func(Item, string) Item) Item {
+	return streams.Reduce(s.Stream, init, f)
+}
@@ -0,0 +1,208 @@
+// Streamgen generates stream wrappers for the given types, with the type
+// changing operations of the streams package as methods, so that pipelines of
+// domain types read left to right:
+//
+//	orders.Filter(paid).FlatMapToItem(items).Collect()
+//
+// instead of nesting streams.Map calls. It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/vkumbhar94/go-streams/cmd/streamgen -types Order,Item,Items=[]Item
+//
+// Every type gets a wrapper named after it, OrderStream for Order, with the
+// intermediate operations returning the wrapper and, for every other type,
+// MapToX, FlatMapToX and ReduceToX methods. Types that are not plain or
+// qualified identifiers must be named, like Items=[]Item above.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wrapped is a type to generate a wrapper for.
+type wrapped struct {
+	Name string
+	Type string
+}
+
+// parseTypes parses a comma separated list of types, each optionally named
+// with Name=Type.
+func parseTypes(spec string) ([]wrapped, error) {
+	var types []wrapped
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, typ, named := strings.Cut(item, "=")
+		if !named {
+			typ = item
+		}
+		expr, err := parser.ParseExpr(typ)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type %q: %v", typ, err)
+		}
+		if !named {
+			switch e := expr.(type) {
+			case *ast.Ident:
+				name = e.Name
+			case *ast.SelectorExpr:
+				name = e.Sel.Name
+			default:
+				return nil, fmt.Errorf("type %q needs a name, use Name=%s", typ, typ)
+			}
+		}
+		if !token(name) {
+			return nil, fmt.Errorf("invalid name %q for type %q", name, typ)
+		}
+		r, size := utf8.DecodeRuneInString(name)
+		name = string(unicode.ToUpper(r)) + name[size:]
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate name %q", name)
+		}
+		seen[name] = true
+		types = append(types, wrapped{Name: name, Type: typ})
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no types")
+	}
+	return types, nil
+}
+
+func token(name string) bool {
+	expr, err := parser.ParseExpr(name)
+	if err != nil {
+		return false
+	}
+	_, ok := expr.(*ast.Ident)
+	return ok
+}
+
+var tmpl = template.Must(template.New("streams").Parse(`// Code generated by streamgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/vkumbhar94/go-streams/pkg/streams"
+)
+{{range $t := .Types}}
+// {{.Name}}Stream is a stream of {{.Type}} with its type changing operations as
+// methods.
+type {{.Name}}Stream struct {
+	*streams.Stream[{{.Type}}]
+}
+
+func New{{.Name}}Stream(s *streams.Stream[{{.Type}}]) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s}
+}
+
+func (s *{{.Name}}Stream) Filter(f streams.FilterFun[{{.Type}}]) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Filter(f)}
+}
+
+func (s *{{.Name}}Stream) Map(mapper streams.UnaryMapFun[{{.Type}}]) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Map(mapper)}
+}
+
+func (s *{{.Name}}Stream) Peek(f func({{.Type}})) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Peek(f)}
+}
+
+func (s *{{.Name}}Stream) Limit(n int) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Limit(n)}
+}
+
+func (s *{{.Name}}Stream) Skip(n int) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Skip(n)}
+}
+
+func (s *{{.Name}}Stream) DropWhile(f func({{.Type}}) bool) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.DropWhile(f)}
+}
+
+func (s *{{.Name}}Stream) TakeWhile(f func({{.Type}}) bool) *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.TakeWhile(f)}
+}
+
+func (s *{{.Name}}Stream) Reverse() *{{.Name}}Stream {
+	return &{{.Name}}Stream{s.Stream.Reverse()}
+}
+{{- range $u := $.Types}}{{if ne $u.Name $t.Name}}
+
+func (s *{{$t.Name}}Stream) MapTo{{$u.Name}}(mapper func({{$t.Type}}) {{$u.Type}}) *{{$u.Name}}Stream {
+	return &{{$u.Name}}Stream{streams.Map(s.Stream, mapper)}
+}
+
+func (s *{{$t.Name}}Stream) FlatMapTo{{$u.Name}}(f func({{$t.Type}}) *streams.Stream[{{$u.Type}}]) *{{$u.Name}}Stream {
+	return &{{$u.Name}}Stream{streams.FlatMap(s.Stream, f)}
+}
+
+func (s *{{$t.Name}}Stream) ReduceTo{{$u.Name}}(init {{$u.Type}}, f func({{$u.Type}}, {{$t.Type}}) {{$u.Type}}) {{$u.Type}} {
+	return streams.Reduce(s.Stream, init, f)
+}
+{{- end}}{{end}}
+{{end}}`))
+
+// generate returns the formatted source of the wrappers of types.
+func generate(pkg string, imports []string, types []wrapped) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Package string
+		Imports []string
+		Types   []wrapped
+	}{pkg, imports, types})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("streamgen: ")
+	types := flag.String("types", "", "comma separated `list` of types, Name=Type to name a type")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to the one go generate runs in")
+	imports := flag.String("imports", "", "comma separated `list` of the import paths the types need")
+	output := flag.String("output", "streams_gen.go", "output file")
+	flag.Parse()
+
+	if *pkg == "" {
+		log.Fatal("no package, use -package outside of go generate")
+	}
+	wrappers, err := parseTypes(*types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var paths []string
+	for _, path := range strings.Split(*imports, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	src, err := generate(*pkg, paths, wrappers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTypes(t *testing.T) {
+	types, err := parseTypes("Order, time.Time,Items=[]Item,string")
+	assert.NoError(t, err)
+	assert.Equal(t, []wrapped{
+		{"Order", "Order"},
+		{"Time", "time.Time"},
+		{"Items", "[]Item"},
+		{"String", "string"},
+	}, types)
+
+	for spec, msg := range map[string]string{
+		"":              "no types",
+		"[]Item":        `type "[]Item" needs a name, use Name=[]Item`,
+		"Order,Order":   `duplicate name "Order"`,
+		"Order,order":   `duplicate name "Order"`,
+		"a b=Order":     `invalid name "a b" for type "Order"`,
+		"Items=[]Item{": `invalid type "[]Item{"`,
+	} {
+		_, err := parseTypes(spec)
+		if assert.Error(t, err, spec) {
+			assert.True(t, strings.HasPrefix(err.Error(), msg), err.Error())
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	types, err := parseTypes("Event,time.Time")
+	assert.NoError(t, err)
+	src, err := generate("events", []string{"time"}, types)
+	assert.NoError(t, err)
+	out := string(src)
+	assert.True(t, strings.HasPrefix(out, "// Code generated by streamgen; DO NOT EDIT.\n\npackage events\n"))
+	assert.Contains(t, out, "\t\"time\"\n")
+	assert.Contains(t, out, "type TimeStream struct {\n\t*streams.Stream[time.Time]\n}")
+	assert.Contains(t, out, "func (s *EventStream) MapToTime(mapper func(Event) time.Time) *TimeStream {")
+	assert.Contains(t, out, "func (s *TimeStream) FlatMapToEvent(f func(time.Time) *streams.Stream[Event]) *EventStream {")
+	assert.NotContains(t, out, "MapToEvent(mapper func(Event)")
+}
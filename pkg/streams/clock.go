@@ -0,0 +1,71 @@
+package streams
+
+import "time"
+
+// Clock is the source of time used by time based operators. It can be replaced
+// to make those operators deterministic in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	// NewTimer returns a stopped timer, so that operators waiting for every
+	// element reuse one timer rather than making one per element.
+	NewTimer() Timer
+}
+
+// Timer is a timer that can be reset, like time.Timer.
+type Timer interface {
+	// C is the channel the current time is sent on when the timer fires.
+	C() <-chan time.Time
+	// Reset makes the timer fire after d, whether it was stopped, running
+	// or fired and not received from yet.
+	Reset(d time.Duration)
+	// Stop stops the timer.
+	Stop()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) NewTimer() Timer {
+	t := time.NewTimer(time.Hour)
+	t.Stop()
+	return systemTimer{t}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Reset(d time.Duration) {
+	t.Stop()
+	t.t.Reset(d)
+}
+
+func (t systemTimer) Stop() {
+	// before go 1.23 a fired timer keeps its value in C until received
+	if !t.t.Stop() {
+		select {
+		case <-t.t.C:
+		default:
+		}
+	}
+}
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock Clock = systemClock{}
@@ -12,52 +12,77 @@ func ToComparableStream[T comparable](s *Stream[T]) *ComparableStream[T] {
 			data: s.data,
 			run:  s.run,
 			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
 		},
 	}
 }
 func (s *ComparableStream[T]) CollectToSet() map[T]struct{} {
-	s.Run()
-	ans := make(map[T]struct{})
-	for t := range s.data {
-		ans[t] = struct{}{}
-
-	}
-	return ans
-}
-
-func (s *ComparableStream[T]) Distinct() *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			seen := make(map[T]struct{})
-			for t := range s.data {
-				if _, ok := seen[t]; !ok {
-					seen[t] = struct{}{}
-					ch <- t
-				}
-			}
-		},
+	return CollectToSet(s.ToStream())
+}
+
+func (s *ComparableStream[T]) Distinct() *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *Distinct(s.ToStream()),
 	}
 }
+
+// DistinctAndThen is Distinct.
+//
+// Deprecated: Distinct returns a ComparableStream as well.
 func (s *ComparableStream[T]) DistinctAndThen() *ComparableStream[T] {
-	ch := make(chan T)
-	return &ComparableStream[T]{
-		Stream[T]{
-			data: ch,
-			run: func() {
-				s.Run()
-				defer close(ch)
-				seen := make(map[T]struct{})
-				for t := range s.data {
-					if _, ok := seen[t]; !ok {
-						seen[t] = struct{}{}
-						ch <- t
-					}
-				}
-			},
-		},
+	return s.Distinct()
+}
+
+func (s *ComparableStream[T]) Peek(f func(T)) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Peek(f),
+	}
+}
+
+func (s *ComparableStream[T]) Filter(f FilterFun[T]) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Filter(f),
+	}
+}
+
+func (s *ComparableStream[T]) Limit(limit int) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Limit(limit),
 	}
 }
+
+func (s *ComparableStream[T]) Skip(skip int) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Skip(skip),
+	}
+}
+
+func (s *ComparableStream[T]) Reverse() *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Reverse(),
+	}
+}
+
+func (s *ComparableStream[T]) Map(mapper UnaryMapFun[T]) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().Map(mapper),
+	}
+}
+
+func (s *ComparableStream[T]) DropWhile(f func(T) bool) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().DropWhile(f),
+	}
+}
+
+func (s *ComparableStream[T]) TakeWhile(f func(T) bool) *ComparableStream[T] {
+	return &ComparableStream[T]{
+		Stream: *s.ToStream().TakeWhile(f),
+	}
+}
+
+func (s *ComparableStream[T]) ToStream() *Stream[T] {
+	return &s.Stream
+}
@@ -0,0 +1,137 @@
+package streams
+
+// Conditional is the outcome of a conditional terminal operation: IfAllMatch,
+// IfAnyMatch, IfNoneMatch, IfEmpty or IfNotEmpty. Then and Else run their
+// action on every element of the stream when the condition held or did not,
+// ThenRun and ElseRun run theirs once. Branches can be chained and run right
+// away, in the order they are given.
+//
+// A conditional terminal operation reads the whole stream and buffers every
+// element before evaluating its condition, since either branch may need them,
+// so it does not short circuit and needs memory for the whole stream.
+type Conditional[T any] struct {
+	matched  bool
+	elements []T
+}
+
+func conditional[T any](s *Stream[T], name string, cond func(elements []T) bool, then []func(T)) *Conditional[T] {
+	var elements []T
+	consume(s, name, func(t T) bool {
+		elements = append(elements, t)
+		return true
+	})
+	c := &Conditional[T]{matched: cond(elements), elements: elements}
+	for _, action := range then {
+		c.Then(action)
+	}
+	return c
+}
+
+// Matched reports whether the condition held.
+func (c *Conditional[T]) Matched() bool {
+	return c.matched
+}
+
+func (c *Conditional[T]) Then(action func(T)) *Conditional[T] {
+	if c.matched {
+		for _, t := range c.elements {
+			action(t)
+		}
+	}
+	return c
+}
+
+func (c *Conditional[T]) Else(action func(T)) *Conditional[T] {
+	if !c.matched {
+		for _, t := range c.elements {
+			action(t)
+		}
+	}
+	return c
+}
+
+func (c *Conditional[T]) ThenRun(action func()) *Conditional[T] {
+	if c.matched {
+		action()
+	}
+	return c
+}
+
+func (c *Conditional[T]) ElseRun(action func()) *Conditional[T] {
+	if !c.matched {
+		action()
+	}
+	return c
+}
+
+func allMatch[T any](elements []T, f func(T) bool) bool {
+	for _, t := range elements {
+		if !f(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyMatch[T any](elements []T, f func(T) bool) bool {
+	for _, t := range elements {
+		if f(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// IfAllMatch checks whether every element satisfies f, which holds for an empty
+// stream. The then actions are run as Then branches.
+func (s *Stream[T]) IfAllMatch(f func(T) bool, then ...func(T)) *Conditional[T] {
+	return conditional(s, "IfAllMatch", func(elements []T) bool {
+		return allMatch(elements, f)
+	}, then)
+}
+
+func (s *Stream[T]) IfAnyMatch(f func(T) bool, then ...func(T)) *Conditional[T] {
+	return conditional(s, "IfAnyMatch", func(elements []T) bool {
+		return anyMatch(elements, f)
+	}, then)
+}
+
+func (s *Stream[T]) IfNoneMatch(f func(T) bool, then ...func(T)) *Conditional[T] {
+	return conditional(s, "IfNoneMatch", func(elements []T) bool {
+		return !anyMatch(elements, f)
+	}, then)
+}
+
+// IfEmpty checks whether s has no element. Its Then branch has no element to
+// run on, use ThenRun.
+func (s *Stream[T]) IfEmpty() *Conditional[T] {
+	return conditional(s, "IfEmpty", func(elements []T) bool {
+		return len(elements) == 0
+	}, nil)
+}
+
+func (s *Stream[T]) IfNotEmpty(then ...func(T)) *Conditional[T] {
+	return conditional(s, "IfNotEmpty", func(elements []T) bool {
+		return len(elements) > 0
+	}, then)
+}
+
+func IfAllMatch[T any](s *Stream[T], f func(T) bool, then ...func(T)) *Conditional[T] {
+	return s.IfAllMatch(f, then...)
+}
+
+func IfAnyMatch[T any](s *Stream[T], f func(T) bool, then ...func(T)) *Conditional[T] {
+	return s.IfAnyMatch(f, then...)
+}
+
+func IfNoneMatch[T any](s *Stream[T], f func(T) bool, then ...func(T)) *Conditional[T] {
+	return s.IfNoneMatch(f, then...)
+}
+
+func IfEmpty[T any](s *Stream[T]) *Conditional[T] {
+	return s.IfEmpty()
+}
+
+func IfNotEmpty[T any](s *Stream[T], then ...func(T)) *Conditional[T] {
+	return s.IfNotEmpty(then...)
+}
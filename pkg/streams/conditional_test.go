@@ -0,0 +1,87 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type branches struct {
+	then, els []int
+	ran       []string
+}
+
+func (b *branches) run(c *Conditional[int]) {
+	c.Then(func(i int) {
+		b.then = append(b.then, i)
+	}).Else(func(i int) {
+		b.els = append(b.els, i)
+	}).ThenRun(func() {
+		b.ran = append(b.ran, "then")
+	}).ElseRun(func() {
+		b.ran = append(b.ran, "else")
+	})
+}
+
+func TestConditional(t *testing.T) {
+	positive := func(i int) bool {
+		return i > 0
+	}
+	for _, tc := range []struct {
+		name    string
+		cond    func(s *Stream[int]) *Conditional[int]
+		input   []int
+		matched bool
+	}{
+		{"IfAllMatch", func(s *Stream[int]) *Conditional[int] { return s.IfAllMatch(positive) }, []int{1, 2}, true},
+		{"IfAllMatch", func(s *Stream[int]) *Conditional[int] { return IfAllMatch(s, positive) }, []int{1, -2}, false},
+		{"IfAllMatch", func(s *Stream[int]) *Conditional[int] { return s.IfAllMatch(positive) }, nil, true},
+		{"IfAnyMatch", func(s *Stream[int]) *Conditional[int] { return s.IfAnyMatch(positive) }, []int{-1, 2}, true},
+		{"IfAnyMatch", func(s *Stream[int]) *Conditional[int] { return IfAnyMatch(s, positive) }, []int{-1, -2}, false},
+		{"IfNoneMatch", func(s *Stream[int]) *Conditional[int] { return s.IfNoneMatch(positive) }, []int{-1, -2}, true},
+		{"IfNoneMatch", func(s *Stream[int]) *Conditional[int] { return IfNoneMatch(s, positive) }, []int{-1, 2}, false},
+		{"IfEmpty", func(s *Stream[int]) *Conditional[int] { return s.IfEmpty() }, nil, true},
+		{"IfEmpty", func(s *Stream[int]) *Conditional[int] { return IfEmpty(s) }, []int{3}, false},
+		{"IfNotEmpty", func(s *Stream[int]) *Conditional[int] { return s.IfNotEmpty() }, []int{3}, true},
+		{"IfNotEmpty", func(s *Stream[int]) *Conditional[int] { return IfNotEmpty(s) }, nil, false},
+	} {
+		var b branches
+		c := tc.cond(FromSlice(tc.input))
+		b.run(c)
+		assert.Equal(t, tc.matched, c.Matched(), "%s %v", tc.name, tc.input)
+		if tc.matched {
+			assert.Equal(t, tc.input, b.then, "%s %v", tc.name, tc.input)
+			assert.Nil(t, b.els)
+			assert.Equal(t, []string{"then"}, b.ran)
+		} else {
+			assert.Nil(t, b.then)
+			assert.Equal(t, tc.input, b.els, "%s %v", tc.name, tc.input)
+			assert.Equal(t, []string{"else"}, b.ran)
+		}
+	}
+}
+
+func TestConditionalThenArguments(t *testing.T) {
+	var then, els []int
+	New(1, 2, 3).IfAnyMatch(func(i int) bool {
+		return i == 2
+	}, func(i int) {
+		then = append(then, i)
+	}).Else(func(i int) {
+		els = append(els, i)
+	})
+	assert.Equal(t, []int{1, 2, 3}, then)
+	assert.Nil(t, els)
+
+	// the condition is evaluated on the buffered elements, the whole stream
+	// is read even though the first element fails it
+	var peeked int
+	IfAllMatch(New(-1, 2, 3).Peek(func(int) {
+		peeked++
+	}), func(i int) bool {
+		return i > 0
+	}, func(int) {
+		t.Error("then branch run")
+	})
+	assert.Equal(t, 3, peeked)
+}
@@ -0,0 +1,253 @@
+package streams
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// CSVOptions configures FromCSV and ToCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' when zero.
+	Comma rune
+	// NoHeader means the records have no header row, columns are then mapped to
+	// the struct fields in declaration order.
+	NoHeader bool
+	// TimeLayout is used for time.Time fields, time.RFC3339 when empty.
+	TimeLayout string
+}
+
+func csvOptions(opts []CSVOptions) CSVOptions {
+	var o CSVOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.Comma == 0 {
+		o.Comma = ','
+	}
+	if o.TimeLayout == "" {
+		o.TimeLayout = time.RFC3339
+	}
+	return o
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// csvFields lists the exported fields of the struct type t named after their
+// csv tag, fields tagged "-" are skipped.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("streams: csv mapping needs a struct, got %s", t)
+	}
+	var fields []csvField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, csvField{name: name, index: f.Index})
+	}
+	return fields, nil
+}
+
+func parseCSVValue(v reflect.Value, s string, layout string) error {
+	if v.Type() == timeType {
+		if s == "" {
+			v.Set(reflect.Zero(timeType))
+			return nil
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if s == "" && v.Kind() != reflect.String {
+		v.SetZero()
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func formatCSVValue(v reflect.Value, layout string) (string, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(layout), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// FromCSV decodes the records read from r into values of the struct type T,
+// mapping columns to fields through their csv tag. The first read or decode
+// error ends the stream and is reported by the error aware terminals.
+func FromCSV[T any](r io.Reader, opts ...CSVOptions) *ErrStream[T] {
+	o := csvOptions(opts)
+	errs := &errHolder{}
+	return toErrStream(source("FromCSV", func(st *stage[T, T]) {
+		fields, err := csvFields(reflect.TypeOf((*T)(nil)).Elem())
+		if err != nil {
+			errs.set(err)
+			return
+		}
+		cr := csv.NewReader(r)
+		cr.Comma = o.Comma
+		cr.FieldsPerRecord = -1
+		columns := make([]*csvField, len(fields))
+		for i := range fields {
+			columns[i] = &fields[i]
+		}
+		if !o.NoHeader {
+			header, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				errs.set(err)
+				return
+			}
+			byName := make(map[string]*csvField, len(fields))
+			for i := range fields {
+				byName[fields[i].name] = &fields[i]
+			}
+			columns = make([]*csvField, len(header))
+			for i, name := range header {
+				columns[i] = byName[name]
+			}
+		}
+		for {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				errs.set(err)
+				return
+			}
+			var t T
+			v := reflect.ValueOf(&t).Elem()
+			for i, value := range record {
+				if i >= len(columns) || columns[i] == nil {
+					continue
+				}
+				if err := parseCSVValue(v.FieldByIndex(columns[i].index), value, o.TimeLayout); err != nil {
+					line, _ := cr.FieldPos(i)
+					errs.set(fmt.Errorf("csv line %d, column %q: %w", line, columns[i].name, err))
+					return
+				}
+			}
+			if !st.send(t) {
+				return
+			}
+		}
+	}), errs)
+}
+
+// ToCSV writes the struct elements of s to w, preceded by a header row unless
+// NoHeader is set.
+func ToCSV[T any](w io.Writer, s *Stream[T], opts ...CSVOptions) error {
+	o := csvOptions(opts)
+	fields, err := csvFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = o.Comma
+	if !o.NoHeader {
+		header := make([]string, len(fields))
+		for i, f := range fields {
+			header[i] = f.name
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(fields))
+	consume(s, "ToCSV", func(t T) bool {
+		v := reflect.ValueOf(t)
+		for i, f := range fields {
+			if record[i], err = formatCSVValue(v.FieldByIndex(f.index), o.TimeLayout); err != nil {
+				err = fmt.Errorf("csv column %q: %w", f.name, err)
+				return false
+			}
+		}
+		err = cw.Write(record)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,81 @@
+package streams
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sale struct {
+	Region string    `csv:"region"`
+	Units  int       `csv:"units"`
+	Price  float64   `csv:"price"`
+	Paid   bool      `csv:"paid"`
+	At     time.Time `csv:"at"`
+	Note   string    `csv:"-"`
+}
+
+const salesCSV = `at,region,units,price,paid,extra
+2024-01-02T10:00:00Z,north,3,1.5,true,x
+2024-01-03T11:30:00Z,south,10,0.25,false,y
+2024-01-04T09:15:00Z,north,,2,true,z
+`
+
+func TestFromCSV(t *testing.T) {
+	collected, err := FromCSV[sale](strings.NewReader(salesCSV)).
+		Filter(func(s sale) bool {
+			return s.Region == "north"
+		}).
+		Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []sale{
+		{Region: "north", Units: 3, Price: 1.5, Paid: true, At: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{Region: "north", Units: 0, Price: 2, Paid: true, At: time.Date(2024, 1, 4, 9, 15, 0, 0, time.UTC)},
+	}, collected)
+}
+
+func TestFromCSVNoHeader(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	collected, err := FromCSV[point](strings.NewReader("1;2\n3;4\n"), CSVOptions{Comma: ';', NoHeader: true}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []point{{1, 2}, {3, 4}}, collected)
+}
+
+func TestFromCSVDecodeError(t *testing.T) {
+	s := FromCSV[sale](strings.NewReader("region,units\nnorth,1\nsouth,many\nwest,2\n"))
+	collected, err := s.Collect()
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Contains(t, err.Error(), `csv line 3, column "units"`)
+	assert.Len(t, collected, 1)
+}
+
+func TestFromCSVNotStruct(t *testing.T) {
+	_, err := FromCSV[int](strings.NewReader("1\n")).Collect()
+	assert.Error(t, err)
+}
+
+func TestToCSV(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(
+		sale{Region: "north", Units: 3, Price: 1.5, Paid: true, At: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Note: "skipped"},
+		sale{Region: "south", Units: 10, Price: 0.25},
+	)
+	assert.NoError(t, ToCSV(&buf, s, CSVOptions{TimeLayout: time.DateOnly}))
+	assert.Equal(t, "region,units,price,paid,at\n"+
+		"north,3,1.5,true,2024-01-02\n"+
+		"south,10,0.25,false,0001-01-01\n", buf.String())
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, ToCSV(&buf, FromCSV[sale](strings.NewReader(salesCSV)).ToStream()))
+	collected, err := FromCSV[sale](&buf).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), collected)
+}
@@ -0,0 +1,123 @@
+package streams
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type errHolder struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (h *errHolder) set(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+func (h *errHolder) get() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// ErrStream is a Stream whose upstream can fail. The first error raised by the
+// upstream ends the stream and is reported by the error aware terminals, which
+// also report a panic of a stage as a *PanicError instead of raising it.
+type ErrStream[T any] struct {
+	Stream[T]
+	err *errHolder
+}
+
+func toErrStream[T any](s *Stream[T], err *errHolder) *ErrStream[T] {
+	return &ErrStream[T]{
+		Stream: Stream[T]{
+			data: s.data,
+			run:  s.run,
+			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
+		},
+		err: err,
+	}
+}
+
+// Err returns the error that ended the stream, if any. It is only meaningful
+// once the stream has been consumed.
+func (s *ErrStream[T]) Err() error {
+	return s.err.get()
+}
+
+// ToStream returns s as a plain Stream, sharing its state so that s runs
+// once whichever of them a terminal is called on.
+func (s *ErrStream[T]) ToStream() *Stream[T] {
+	return &s.Stream
+}
+
+// recoverPanic is deferred by the error aware terminals to report the panic of
+// a stage through err.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		perr, ok := r.(*PanicError)
+		if !ok {
+			panic(r)
+		}
+		*err = perr
+	}
+}
+
+func (s *ErrStream[T]) Collect() (result []T, err error) {
+	defer recoverPanic(&err)
+	result = s.ToStream().Collect()
+	return result, s.Err()
+}
+
+func (s *ErrStream[T]) ForEach(f func(T)) (err error) {
+	defer recoverPanic(&err)
+	s.ToStream().ForEach(f)
+	return s.Err()
+}
+
+func (s *ErrStream[T]) Count() (cnt int64, err error) {
+	defer recoverPanic(&err)
+	cnt = s.ToStream().Count()
+	return cnt, s.Err()
+}
+
+func (s *ErrStream[T]) Filter(f FilterFun[T]) *ErrStream[T] {
+	return toErrStream(s.ToStream().Filter(f), s.err)
+}
+
+func (s *ErrStream[T]) Map(mapper UnaryMapFun[T]) *ErrStream[T] {
+	return toErrStream(s.ToStream().Map(mapper), s.err)
+}
+
+func (s *ErrStream[T]) Peek(f func(T)) *ErrStream[T] {
+	return toErrStream(s.ToStream().Peek(f), s.err)
+}
+
+func (s *ErrStream[T]) Limit(limit int) *ErrStream[T] {
+	return toErrStream(s.ToStream().Limit(limit), s.err)
+}
+
+func (s *ErrStream[T]) Skip(skip int) *ErrStream[T] {
+	return toErrStream(s.ToStream().Skip(skip), s.err)
+}
+
+func (s *ErrStream[T]) DropWhile(f func(T) bool) *ErrStream[T] {
+	return toErrStream(s.ToStream().DropWhile(f), s.err)
+}
+
+func (s *ErrStream[T]) TakeWhile(f func(T) bool) *ErrStream[T] {
+	return toErrStream(s.ToStream().TakeWhile(f), s.err)
+}
+
+// MapErrStream applies mapper to every element while keeping the error of the
+// upstream ErrStream.
+func MapErrStream[T, R any](s *ErrStream[T], mapper MapFun[T, R]) *ErrStream[R] {
+	return toErrStream(Map(s.ToStream(), mapper), s.err)
+}
@@ -0,0 +1,117 @@
+package streams
+
+// The indexed operations pass every element along with its position in the
+// stream they are applied to, starting at 0. That is the position once the
+// upstream operations ran, not in the source: after a Filter dropping the
+// second element, the third one is at position 1. Positions are counted by the
+// stage running the operation, so they do not depend on how the stages are
+// scheduled.
+
+// positioned returns the steps of an indexed operation calling f with the
+// position of every element.
+func positioned[T, R any](f func(i int, t T) (R, bool)) func() func(T) (R, bool) {
+	return func() func(T) (R, bool) {
+		i := 0
+		return func(t T) (R, bool) {
+			r, ok := f(i, t)
+			i++
+			return r, ok
+		}
+	}
+}
+
+func MapIndexed[T, R any](s *Stream[T], mapper func(i int, t T) R) *Stream[R] {
+	mapped := fuseStep(s, "MapIndexed", positioned(func(i int, t T) (R, bool) {
+		return mapper(i, t), true
+	}))
+	mapped.opt.limit = func(n int) *Stream[R] {
+		return MapIndexed(s.Limit(n), mapper)
+	}
+	return mapped
+}
+
+func (s *Stream[T]) FilterIndexed(filter func(i int, t T) bool) *Stream[T] {
+	return fuseStep(s, "FilterIndexed", positioned(func(i int, t T) (T, bool) {
+		return t, filter(i, t)
+	}))
+}
+
+func (s *Stream[T]) PeekIndexed(f func(i int, t T)) *Stream[T] {
+	return fuseStep(s, "PeekIndexed", positioned(func(i int, t T) (T, bool) {
+		f(i, t)
+		return t, true
+	}))
+}
+
+func (s *Stream[T]) TakeWhileIndexed(f func(i int, t T) bool) *Stream[T] {
+	return derive(s, "TakeWhileIndexed", func(st *stage[T, T]) {
+		i := 0
+		st.each(func(t T) bool {
+			if !f(i, t) {
+				return false
+			}
+			i++
+			st.send(t)
+			return true
+		})
+	})
+}
+
+func (s *Stream[T]) ForEachIndexed(f func(i int, t T)) {
+	i := 0
+	consume(s, "ForEachIndexed", func(t T) bool {
+		f(i, t)
+		i++
+		return true
+	})
+}
+
+// ElementAt returns the element at position n, or nil when s has fewer
+// elements.
+func (s *Stream[T]) ElementAt(n int) *T {
+	var found *T
+	i := 0
+	consume(s, "ElementAt", func(t T) bool {
+		if i == n {
+			found = &t
+			return false
+		}
+		i++
+		return n >= 0
+	})
+	return found
+}
+
+// Last returns the last element, or nil when s is empty.
+func (s *Stream[T]) Last() *T {
+	var last *T
+	consume(s, "Last", func(t T) bool {
+		last = &t
+		return true
+	})
+	return last
+}
+
+func FilterIndexed[T any](s *Stream[T], filter func(i int, t T) bool) *Stream[T] {
+	return s.FilterIndexed(filter)
+}
+
+func PeekIndexed[T any](s *Stream[T], f func(i int, t T)) *Stream[T] {
+	return s.PeekIndexed(f)
+}
+
+func TakeWhileIndexed[T any](s *Stream[T], f func(i int, t T) bool) *Stream[T] {
+	return s.TakeWhileIndexed(f)
+}
+
+func ForEachIndexed[T any](s *Stream[T], f func(i int, t T)) {
+	s.ForEachIndexed(f)
+}
+
+func ElementAt[T any](s *Stream[T], n int) *T {
+	return s.ElementAt(n)
+}
+
+func Last[T any](s *Stream[T]) *T {
+	return s.Last()
+}
@@ -0,0 +1,67 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexed(t *testing.T) {
+	var peeked []int
+	s := New("a", "b", "c", "d", "e").
+		FilterIndexed(func(i int, _ string) bool {
+			return i != 1
+		}).
+		PeekIndexed(func(i int, _ string) {
+			peeked = append(peeked, i)
+		})
+	labels := MapIndexed(s, func(i int, s string) string {
+		return fmt.Sprintf("%d:%s", i, s)
+	}).Collect()
+	// positions are counted after FilterIndexed dropped "b"
+	assert.Equal(t, []string{"0:a", "1:c", "2:d", "3:e"}, labels)
+	assert.Equal(t, []int{0, 1, 2, 3}, peeked)
+
+	taken := New(5, 6, 7, 1, 9).TakeWhileIndexed(func(i, v int) bool {
+		return v > i
+	}).Collect()
+	assert.Equal(t, []int{5, 6, 7}, taken)
+
+	var sum int
+	New(10, 20, 30).ForEachIndexed(func(i, v int) {
+		sum += i * v
+	})
+	assert.Equal(t, 80, sum)
+}
+
+func TestTakeWhileIndexedExplain(t *testing.T) {
+	s := New(1, 2).TakeWhileIndexed(func(i, v int) bool {
+		return true
+	})
+	assert.Equal(t, "#1 TakeWhileIndexed [short-circuit]\n"+
+		"└─ #0 New(2 elements)\n", s.Explain())
+}
+
+func TestMapIndexedLimit(t *testing.T) {
+	var calls int
+	s := MapIndexed(New(1, 2, 3, 4), func(i, v int) int {
+		calls++
+		return i * v
+	}).Limit(3)
+	assert.Equal(t, []int{0, 2, 6}, s.Collect())
+	assert.Equal(t, 3, calls)
+}
+
+func TestElementAt(t *testing.T) {
+	assert.Equal(t, 30, *New(10, 20, 30, 40).ElementAt(2))
+	assert.Equal(t, 10, *ElementAt(New(10, 20), 0))
+	assert.Nil(t, New(10, 20).ElementAt(2))
+	assert.Nil(t, New(10, 20).ElementAt(-1))
+}
+
+func TestLast(t *testing.T) {
+	assert.Equal(t, 3, *New(1, 2, 3).Last())
+	assert.Equal(t, "b", *Last(New("a", "b")))
+	assert.Nil(t, New[int]().Last())
+}
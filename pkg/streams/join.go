@@ -0,0 +1,284 @@
+package streams
+
+import (
+	"cmp"
+	"fmt"
+)
+
+type Pair[L, R any] struct {
+	Left  L
+	Right R
+}
+
+func (p Pair[L, R]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.Left, p.Right)
+}
+
+// readSmaller consumes left and right alternately until one of them is
+// exhausted, so that only the smaller side ends up fully buffered. The
+// elements read are counted by m.
+func readSmaller[L, R any](m *meter, left *Stream[L], right *Stream[R]) (ls []L, rs []R, leftDone bool) {
+	for {
+		l, ok := <-left.data
+		if !ok {
+			return ls, rs, true
+		}
+		m.received()
+		ls = append(ls, l)
+		r, ok := <-right.data
+		if !ok {
+			return ls, rs, false
+		}
+		m.received()
+		rs = append(rs, r)
+	}
+}
+
+// probe builds a hash index over the fully read build side and streams the
+// probe side through it. Build elements that never matched are reported
+// through unmatched when it is set. The elements read from rest are counted by
+// m. Probing stops as soon as match or unmatched returns false, once the output
+// is no longer read.
+func probe[B, P any, K comparable](m *meter, build []B, buildKey func(B) K, buffered []P, rest <-chan P, probeKey func(P) K, match func(b *B, p *P) bool, unmatched func(b *B) bool) {
+	index := make(map[K][]int)
+	for i, b := range build {
+		k := buildKey(b)
+		index[k] = append(index[k], i)
+	}
+	matched := make([]bool, len(build))
+	visit := func(p P) bool {
+		positions := index[probeKey(p)]
+		if len(positions) == 0 {
+			return match(nil, &p)
+		}
+		for _, i := range positions {
+			matched[i] = true
+			b := build[i]
+			if !match(&b, &p) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, p := range buffered {
+		if !visit(p) {
+			return
+		}
+	}
+	for p := range rest {
+		m.received()
+		if !visit(p) {
+			return
+		}
+	}
+	if unmatched != nil {
+		for i := range build {
+			if !matched[i] {
+				b := build[i]
+				if !unmatched(&b) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func hashJoin[L, R any, K comparable](name string, left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K, keepLeft, keepRight bool) *Stream[Pair[*L, *R]] {
+	left.pipeline().merge(right.pipeline())
+	return withInput(derive(left, name, func(st *stage[L, Pair[*L, *R]]) {
+		right.Run()
+		// the inputs are not read to the end when the output is no longer
+		// read or a key function panics
+		defer func() {
+			stopStream(left.node, left.data)
+			stopStream(right.node, right.data)
+		}()
+		emit := func(l *L, r *R) bool {
+			if (l != nil || keepRight) && (r != nil || keepLeft) {
+				return st.send(Pair[*L, *R]{l, r})
+			}
+			return true
+		}
+		ls, rs, leftDone := readSmaller(st.meter, left, right)
+		if leftDone {
+			var unmatched func(*L) bool
+			if keepLeft {
+				unmatched = func(l *L) bool {
+					return emit(l, nil)
+				}
+			}
+			probe(st.meter, ls, leftKey, rs, right.data, rightKey, emit, unmatched)
+			if !st.stopped {
+				rethrow(left.node, right.node)
+			}
+			return
+		}
+		var unmatched func(*R) bool
+		if keepRight {
+			unmatched = func(r *R) bool {
+				return emit(nil, r)
+			}
+		}
+		probe(st.meter, rs, rightKey, ls, left.data, leftKey, func(r *R, l *L) bool {
+			return emit(l, r)
+		}, unmatched)
+		if !st.stopped {
+			rethrow(left.node, right.node)
+		}
+	}), right)
+}
+
+// Join pairs every left element with every right element of the same key.
+// It is a hash join buffering the smaller input, the pairs follow the order of
+// the larger one.
+func Join[L, R any, K comparable](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[Pair[L, R]] {
+	return Map(hashJoin("Join", left, right, leftKey, rightKey, false, false), func(p Pair[*L, *R]) Pair[L, R] {
+		return Pair[L, R]{*p.Left, *p.Right}
+	})
+}
+
+// LeftJoin is like Join but also keeps the left elements without a match,
+// paired with a nil Right.
+func LeftJoin[L, R any, K comparable](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[Pair[L, *R]] {
+	return Map(hashJoin("LeftJoin", left, right, leftKey, rightKey, true, false), func(p Pair[*L, *R]) Pair[L, *R] {
+		return Pair[L, *R]{*p.Left, p.Right}
+	})
+}
+
+// FullOuterJoin is like Join but also keeps the elements of either side
+// without a match, paired with nil.
+func FullOuterJoin[L, R any, K comparable](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[Pair[*L, *R]] {
+	return hashJoin("FullOuterJoin", left, right, leftKey, rightKey, true, true)
+}
+
+func filterJoin[L, R any, K comparable](name string, left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K, keepMatched bool) *Stream[L] {
+	left.pipeline().merge(right.pipeline())
+	return withInput(derive(left, name, func(st *stage[L, L]) {
+		right.Run()
+		// the inputs are not read to the end when the output is no longer
+		// read or a key function panics
+		defer func() {
+			stopStream(left.node, left.data)
+			stopStream(right.node, right.data)
+		}()
+		ls, rs, leftDone := readSmaller(st.meter, left, right)
+		if leftDone {
+			matched := make(map[K]bool)
+			for _, l := range ls {
+				matched[leftKey(l)] = false
+			}
+			for _, r := range rs {
+				matched[rightKey(r)] = true
+			}
+			for r := range right.data {
+				st.received()
+				matched[rightKey(r)] = true
+			}
+			rethrow(left.node, right.node)
+			for _, l := range ls {
+				if matched[leftKey(l)] == keepMatched && !st.send(l) {
+					return
+				}
+			}
+			return
+		}
+		keys := make(map[K]struct{})
+		for _, r := range rs {
+			keys[rightKey(r)] = struct{}{}
+		}
+		visit := func(l L) bool {
+			if _, ok := keys[leftKey(l)]; ok == keepMatched {
+				return st.send(l)
+			}
+			return true
+		}
+		for _, l := range ls {
+			if !visit(l) {
+				return
+			}
+		}
+		for l := range left.data {
+			st.received()
+			if !visit(l) {
+				return
+			}
+		}
+		rethrow(left.node, right.node)
+	}), right)
+}
+
+// SemiJoin keeps the left elements having at least one right element of the
+// same key, in their original order.
+func SemiJoin[L, R any, K comparable](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[L] {
+	return filterJoin("SemiJoin", left, right, leftKey, rightKey, true)
+}
+
+// AntiJoin keeps the left elements having no right element of the same key,
+// in their original order.
+func AntiJoin[L, R any, K comparable](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[L] {
+	return filterJoin("AntiJoin", left, right, leftKey, rightKey, false)
+}
+
+// MergeJoin is an inner join of two inputs sorted in ascending key order, such
+// as the output of Sorted. Only the elements sharing the current key are
+// buffered.
+func MergeJoin[L, R any, K cmp.Ordered](left *Stream[L], right *Stream[R], leftKey func(L) K, rightKey func(R) K) *Stream[Pair[L, R]] {
+	left.pipeline().merge(right.pipeline())
+	return withInput(derive(left, "MergeJoin", func(st *stage[L, Pair[L, R]]) {
+		right.Run()
+		defer func() {
+			stopStream(left.node, left.data)
+			stopStream(right.node, right.data)
+		}()
+		var l L
+		var r R
+		var lok, rok bool
+		nextLeft := func() {
+			if l, lok = <-left.data; lok {
+				st.received()
+			}
+		}
+		nextRight := func() {
+			if r, rok = <-right.data; rok {
+				st.received()
+			}
+		}
+		nextLeft()
+		nextRight()
+		for lok && rok {
+			lk, rk := leftKey(l), rightKey(r)
+			switch {
+			case lk < rk:
+				nextLeft()
+			case lk > rk:
+				nextRight()
+			default:
+				var ls []L
+				for lok && leftKey(l) == lk {
+					ls = append(ls, l)
+					nextLeft()
+				}
+				var rs []R
+				for rok && rightKey(r) == rk {
+					rs = append(rs, r)
+					nextRight()
+				}
+				for _, l := range ls {
+					for _, r := range rs {
+						if !st.send(Pair[L, R]{l, r}) {
+							return
+						}
+					}
+				}
+			}
+		}
+		// the join ended with the first input to end, which may have
+		// panicked
+		if !lok {
+			rethrow(left.node)
+		}
+		if !rok {
+			rethrow(right.node)
+		}
+	}), right)
+}
@@ -0,0 +1,119 @@
+package streams
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	id   int
+	name string
+}
+
+type order struct {
+	userID int
+	item   string
+}
+
+func users() *Stream[user] {
+	return New(user{1, "ann"}, user{2, "bob"}, user{3, "cid"})
+}
+
+func orders() *Stream[order] {
+	return New(order{1, "pen"}, order{3, "ink"}, order{1, "cup"}, order{4, "map"}, order{3, "box"}, order{1, "hat"})
+}
+
+func userID(u user) int {
+	return u.id
+}
+
+func orderUserID(o order) int {
+	return o.userID
+}
+
+func TestJoin(t *testing.T) {
+	collected := Collect(Map(Join(users(), orders(), userID, orderUserID), func(p Pair[user, order]) string {
+		return p.Left.name + ":" + p.Right.item
+	}))
+	assert.Equal(t, []string{"ann:pen", "cid:ink", "ann:cup", "cid:box", "ann:hat"}, collected)
+}
+
+func TestJoinLargerLeft(t *testing.T) {
+	collected := Collect(Map(Join(orders(), users(), orderUserID, userID), func(p Pair[order, user]) string {
+		return p.Right.name + ":" + p.Left.item
+	}))
+	assert.Equal(t, []string{"ann:pen", "cid:ink", "ann:cup", "cid:box", "ann:hat"}, collected)
+}
+
+func TestLeftJoin(t *testing.T) {
+	collected := Collect(Map(LeftJoin(orders(), users(), orderUserID, userID), func(p Pair[order, *user]) string {
+		if p.Right == nil {
+			return "?:" + p.Left.item
+		}
+		return p.Right.name + ":" + p.Left.item
+	}))
+	assert.Equal(t, []string{"ann:pen", "cid:ink", "ann:cup", "?:map", "cid:box", "ann:hat"}, collected)
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	collected := Collect(Map(FullOuterJoin(users(), orders(), userID, orderUserID), func(p Pair[*user, *order]) string {
+		name, item := "?", "?"
+		if p.Left != nil {
+			name = p.Left.name
+		}
+		if p.Right != nil {
+			item = p.Right.item
+		}
+		return name + ":" + item
+	}))
+	assert.Equal(t, []string{"ann:pen", "cid:ink", "ann:cup", "?:map", "cid:box", "ann:hat", "bob:?"}, collected)
+}
+
+func TestSemiAndAntiJoin(t *testing.T) {
+	assert.Equal(t, []user{{1, "ann"}, {3, "cid"}}, Collect(SemiJoin(users(), orders(), userID, orderUserID)))
+	assert.Equal(t, []user{{2, "bob"}}, Collect(AntiJoin(users(), orders(), userID, orderUserID)))
+	assert.Equal(t, []order{{4, "map"}}, Collect(AntiJoin(orders(), users(), orderUserID, userID)))
+}
+
+func TestMergeJoin(t *testing.T) {
+	left := Sorted(New(3, 1, 2, 2, 5), ASC)
+	right := Sorted(New(2, 5, 4, 2, 6), ASC)
+	collected := Collect(MergeJoin(left, right, func(i int) int {
+		return i
+	}, func(i int) int {
+		return i
+	}))
+	assert.Equal(t, []Pair[int, int]{{2, 2}, {2, 2}, {2, 2}, {2, 2}, {5, 5}}, collected)
+}
+
+func TestJoinEmpty(t *testing.T) {
+	assert.Empty(t, Collect(Join(New[user](), orders(), userID, orderUserID)))
+	assert.Empty(t, Collect(Join(users(), New[order](), userID, orderUserID)))
+}
+
+func TestJoinShortCircuit(t *testing.T) {
+	const n = 1000
+	id := func(i int) int {
+		return i
+	}
+	var read atomic.Int64
+	joined := Join(counted(n, &read), New(0, 1, 2), id, id).Limit(1).Collect()
+	assert.Equal(t, []Pair[int, int]{{0, 0}}, joined)
+	assertStopped(t, &read, n)
+
+	read.Store(0)
+	outer := FullOuterJoin(New(0, 1, 2), counted(n, &read), id, id).Limit(1).Collect()
+	assert.Len(t, outer, 1)
+	assertStopped(t, &read, n)
+
+	read.Store(0)
+	assert.Equal(t, []int{1}, SemiJoin(counted(n, &read), New(1, 2), id, id).Limit(1).Collect())
+	assertStopped(t, &read, n)
+
+	read.Store(0)
+	merged := MergeJoin(counted(n, &read), counted(n, &read), id, id).Limit(1).Collect()
+	assert.Equal(t, []Pair[int, int]{{0, 0}}, merged)
+	assertStopped(t, &read, 2*n)
+}
@@ -0,0 +1,129 @@
+package streams
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeError reports a record that could not be decoded. Record is the
+// 1-based line number for JSON Lines and element number for JSON arrays.
+type DecodeError struct {
+	Record int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("streams: record %d: %v", e.Record, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// JSONOptions configures FromJSONLines and FromJSONArray.
+type JSONOptions struct {
+	// OnDecodeError, when set, receives the records that cannot be decoded
+	// and the stream goes on without them. Otherwise the first DecodeError
+	// ends the stream.
+	OnDecodeError func(*DecodeError)
+}
+
+// decodeFailed reports whether the stream has to stop because of err.
+func (o JSONOptions) decodeFailed(errs *errHolder, err *DecodeError) bool {
+	if o.OnDecodeError == nil {
+		errs.set(err)
+		return true
+	}
+	o.OnDecodeError(err)
+	return false
+}
+
+func jsonOptions(opts []JSONOptions) JSONOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return JSONOptions{}
+}
+
+// FromJSONLines decodes every non blank line read from r as a T. Read errors
+// end the stream and are reported by the error aware terminals.
+func FromJSONLines[T any](r io.Reader, opts ...JSONOptions) *ErrStream[T] {
+	o := jsonOptions(opts)
+	errs := &errHolder{}
+	return toErrStream(source("FromJSONLines", func(st *stage[T, T]) {
+		br := bufio.NewReader(r)
+		for n := 1; ; n++ {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				var t T
+				if derr := json.Unmarshal(line, &t); derr != nil {
+					if o.decodeFailed(errs, &DecodeError{Record: n, Err: derr}) {
+						return
+					}
+				} else if !st.send(t) {
+					return
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				errs.set(err)
+				return
+			}
+		}
+	}), errs)
+}
+
+// FromJSONArray decodes the elements of the top level JSON array read from r
+// one at a time, without loading the whole array. Elements of the wrong type
+// are decode errors, malformed JSON ends the stream.
+func FromJSONArray[T any](r io.Reader, opts ...JSONOptions) *ErrStream[T] {
+	o := jsonOptions(opts)
+	errs := &errHolder{}
+	return toErrStream(source("FromJSONArray", func(st *stage[T, T]) {
+		dec := json.NewDecoder(r)
+		if tok, err := dec.Token(); err != nil {
+			errs.set(err)
+			return
+		} else if tok != json.Delim('[') {
+			errs.set(fmt.Errorf("streams: expected JSON array, got %v", tok))
+			return
+		}
+		for n := 1; dec.More(); n++ {
+			var t T
+			if err := dec.Decode(&t); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &typeErr) {
+					errs.set(err)
+					return
+				}
+				if o.decodeFailed(errs, &DecodeError{Record: n, Err: err}) {
+					return
+				}
+				continue
+			}
+			if !st.send(t) {
+				return
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			errs.set(err)
+		}
+	}), errs)
+}
+
+// ToJSONLines writes every element of s to w as one line of JSON.
+func ToJSONLines[T any](w io.Writer, s *Stream[T]) error {
+	enc := json.NewEncoder(w)
+	var err error
+	consume(s, "ToJSONLines", func(t T) bool {
+		err = enc.Encode(t)
+		return err == nil
+	})
+	return err
+}
@@ -0,0 +1,93 @@
+package streams
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logLine struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+const logLines = `{"level":"info","msg":"start"}
+{"level":"error","msg":"disk full"}
+
+{"level":"info","msg":"retry"}
+{"level":"error","msg":"gave up"}`
+
+func TestFromJSONLines(t *testing.T) {
+	collected, err := MapErrStream(
+		FromJSONLines[logLine](strings.NewReader(logLines)).
+			Filter(func(l logLine) bool {
+				return l.Level == "error"
+			}),
+		func(l logLine) string {
+			return l.Msg
+		},
+	).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"disk full", "gave up"}, collected)
+}
+
+func TestFromJSONLinesDecodeError(t *testing.T) {
+	input := "{\"level\":\"info\"}\nnot json\n{\"level\":\"warn\"}\n"
+
+	collected, err := FromJSONLines[logLine](strings.NewReader(input)).Collect()
+	var decodeErr *DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, 2, decodeErr.Record)
+	assert.Len(t, collected, 1)
+
+	var skipped []int
+	collected, err = FromJSONLines[logLine](strings.NewReader(input), JSONOptions{
+		OnDecodeError: func(e *DecodeError) {
+			skipped = append(skipped, e.Record)
+		},
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []logLine{{Level: "info"}, {Level: "warn"}}, collected)
+	assert.Equal(t, []int{2}, skipped)
+}
+
+func TestFromJSONArray(t *testing.T) {
+	collected, err := FromJSONArray[int](strings.NewReader(" [1, 2, 3, 4] ")).
+		Filter(func(i int) bool {
+			return i%2 == 0
+		}).
+		Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, collected)
+}
+
+func TestFromJSONArrayErrors(t *testing.T) {
+	var skipped []int
+	collected, err := FromJSONArray[int](strings.NewReader(`[1, "two", 3]`), JSONOptions{
+		OnDecodeError: func(e *DecodeError) {
+			skipped = append(skipped, e.Record)
+		},
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, collected)
+	assert.Equal(t, []int{2}, skipped)
+
+	_, err = FromJSONArray[int](strings.NewReader(`{"a": 1}`)).Collect()
+	assert.Error(t, err)
+
+	collected, err = FromJSONArray[int](strings.NewReader(`[1, 2`)).Collect()
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, collected)
+}
+
+func TestToJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, ToJSONLines(&buf, New(logLine{"info", "a"}, logLine{"warn", "b"})))
+	assert.Equal(t, "{\"level\":\"info\",\"msg\":\"a\"}\n{\"level\":\"warn\",\"msg\":\"b\"}\n", buf.String())
+
+	collected, err := FromJSONLines[logLine](&buf).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), collected)
+}
@@ -0,0 +1,118 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is reported by MCollectMerge with the ErrOnDuplicate policy.
+var ErrDuplicateKey = errors.New("streams: duplicate key")
+
+// MergeFun resolves a key collision between the value already collected and a
+// newly seen one.
+type MergeFun[V any] func(existing, incoming V) (V, error)
+
+// KeepFirst is a MergeFun that keeps the value seen first.
+func KeepFirst[V any](existing, _ V) (V, error) {
+	return existing, nil
+}
+
+// KeepLast is a MergeFun that keeps the value seen last, like MCollect does.
+func KeepLast[V any](_, incoming V) (V, error) {
+	return incoming, nil
+}
+
+// ErrOnDuplicate is a MergeFun that fails on any collision.
+func ErrOnDuplicate[V any](existing, _ V) (V, error) {
+	return existing, ErrDuplicateKey
+}
+
+// MergeWith returns a MergeFun combining colliding values with f.
+func MergeWith[V any](f func(existing, incoming V) V) MergeFun[V] {
+	return func(existing, incoming V) (V, error) {
+		return f(existing, incoming), nil
+	}
+}
+
+// MCollectMerge collects the stream into a map resolving duplicate keys with
+// merge. The first error returned by merge stops the collection and is
+// returned with a nil map.
+func MCollectMerge[K comparable, V any](s *Stream[MapEntry[K, V]], merge MergeFun[V]) (map[K]V, error) {
+	result := make(map[K]V)
+	var err error
+	consume(s, "MCollectMerge", func(t MapEntry[K, V]) bool {
+		existing, ok := result[t.K]
+		if !ok {
+			result[t.K] = t.V
+			return true
+		}
+		v, mergeErr := merge(existing, t.V)
+		if mergeErr != nil {
+			err = fmt.Errorf("key %v: %w", t.K, mergeErr)
+			return false
+		}
+		result[t.K] = v
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AggregateByKey folds the values of every key into an accumulator of its own,
+// made by init when the key is first seen. Keys are emitted in the order they
+// were first seen.
+// Stateful Intermediate Operation.
+func AggregateByKey[K comparable, V, A any](s *Stream[MapEntry[K, V]], init func() A, f func(acc A, v V) A) *Stream[MapEntry[K, A]] {
+	return derive(s, "AggregateByKey", func(st *stage[MapEntry[K, V], MapEntry[K, A]]) {
+		var keys []K
+		acc := make(map[K]A)
+		st.each(func(t MapEntry[K, V]) bool {
+			a, ok := acc[t.K]
+			if !ok {
+				keys = append(keys, t.K)
+				a = init()
+			}
+			acc[t.K] = f(a, t.V)
+			return true
+		})
+		for _, k := range keys {
+			st.send(MapEntry[K, A]{k, acc[k]})
+		}
+	})
+}
+
+// ReduceByKey merges the values of every key. Keys are emitted in the order
+// they were first seen.
+// Stateful Intermediate Operation.
+func ReduceByKey[K comparable, V any](s *Stream[MapEntry[K, V]], merge func(V, V) V) *Stream[MapEntry[K, V]] {
+	return derive(s, "ReduceByKey", func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		var keys []K
+		reduced := make(map[K]V)
+		st.each(func(t MapEntry[K, V]) bool {
+			v, ok := reduced[t.K]
+			if !ok {
+				keys = append(keys, t.K)
+				reduced[t.K] = t.V
+				return true
+			}
+			reduced[t.K] = merge(v, t.V)
+			return true
+		})
+		for _, k := range keys {
+			st.send(MapEntry[K, V]{k, reduced[k]})
+		}
+	})
+}
+
+// CountByKey counts the entries of every key. Keys are emitted in the order
+// they were first seen.
+// Stateful Intermediate Operation.
+func CountByKey[K comparable, V any](s *Stream[MapEntry[K, V]]) *Stream[MapEntry[K, int64]] {
+	return AggregateByKey(s, func() int64 {
+		return 0
+	}, func(cnt int64, _ V) int64 {
+		return cnt + 1
+	})
+}
@@ -0,0 +1,81 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func entries() *Stream[MapEntry[string, int]] {
+	return New(
+		MapEntry[string, int]{"a", 1},
+		MapEntry[string, int]{"b", 2},
+		MapEntry[string, int]{"a", 3},
+		MapEntry[string, int]{"c", 4},
+		MapEntry[string, int]{"b", 5},
+	)
+}
+
+func TestReduceByKey(t *testing.T) {
+	collected := Collect(ReduceByKey(entries(), func(a, b int) int {
+		return a + b
+	}))
+	assert.Equal(t, []MapEntry[string, int]{{"a", 4}, {"b", 7}, {"c", 4}}, collected)
+}
+
+func TestAggregateByKey(t *testing.T) {
+	collected := Collect(AggregateByKey(entries(), func() []int {
+		return nil
+	}, func(acc []int, v int) []int {
+		return append(acc, v)
+	}))
+	assert.Equal(t, []MapEntry[string, []int]{{"a", []int{1, 3}}, {"b", []int{2, 5}}, {"c", []int{4}}}, collected)
+
+	// every key gets an accumulator of its own
+	sets := MCollect(AggregateByKey(entries(), func() map[int]bool {
+		return make(map[int]bool)
+	}, func(acc map[int]bool, v int) map[int]bool {
+		acc[v] = true
+		return acc
+	}))
+	assert.Equal(t, map[int]bool{2: true, 5: true}, sets["b"])
+}
+
+func TestCountByKeyWordCount(t *testing.T) {
+	words := New(strings.Fields("the quick fox jumps over the lazy fox the end")...)
+	counts := MCollect(CountByKey(Map(words, func(w string) MapEntry[string, struct{}] {
+		return MapEntry[string, struct{}]{K: w}
+	})))
+	assert.Equal(t, int64(3), counts["the"])
+	assert.Equal(t, int64(2), counts["fox"])
+	assert.Equal(t, int64(1), counts["end"])
+	assert.Len(t, counts, 7)
+}
+
+func TestMCollectMerge(t *testing.T) {
+	first, err := MCollectMerge(entries(), KeepFirst)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 4}, first)
+
+	last, err := MCollectMerge(entries(), KeepLast)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 3, "b": 5, "c": 4}, last)
+
+	sum, err := MCollectMerge(entries(), MergeWith(func(a, b int) int {
+		return a + b
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 4, "b": 7, "c": 4}, sum)
+}
+
+func TestMCollectMergeErrOnDuplicate(t *testing.T) {
+	m, err := MCollectMerge(entries(), ErrOnDuplicate)
+	assert.Nil(t, m)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+	assert.Contains(t, err.Error(), "key a")
+
+	unique, err := MCollectMerge(MNew(map[string]int{"x": 1, "y": 2}), ErrOnDuplicate)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, unique)
+}
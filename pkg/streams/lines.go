@@ -0,0 +1,63 @@
+package streams
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+func scan(name string, open func() (*bufio.Scanner, io.Closer, error)) *ErrStream[string] {
+	errs := &errHolder{}
+	return toErrStream(source(name, func(st *stage[string, string]) {
+		sc, closer, err := open()
+		if err != nil {
+			errs.set(err)
+			return
+		}
+		if closer != nil {
+			defer func() {
+				if err := closer.Close(); err != nil {
+					errs.set(err)
+				}
+			}()
+		}
+		for sc.Scan() {
+			if !st.send(sc.Text()) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			errs.set(err)
+		}
+	}), errs)
+}
+
+// FromScanner streams the tokens of sc. A scan error ends the stream and is
+// reported by the error aware terminals.
+func FromScanner(sc *bufio.Scanner) *ErrStream[string] {
+	return scan("FromScanner", func() (*bufio.Scanner, io.Closer, error) {
+		return sc, nil, nil
+	})
+}
+
+// Lines streams the lines of r without their line endings.
+func Lines(r io.Reader) *ErrStream[string] {
+	return scan("Lines", func() (*bufio.Scanner, io.Closer, error) {
+		return bufio.NewScanner(r), nil, nil
+	})
+}
+
+// FromFile streams the lines of the file at path. The file is opened when the
+// stream runs and closed once every line has been read, or as soon as a short
+// circuiting operation such as Limit or FindFirst stopped reading lines.
+func FromFile(path string) *ErrStream[string] {
+	s := scan("FromFile", func() (*bufio.Scanner, io.Closer, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bufio.NewScanner(f), f, nil
+	})
+	s.withArgs(path)
+	return s
+}
@@ -0,0 +1,107 @@
+package streams
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	collected, err := Lines(strings.NewReader("a\nbb\r\nccc\n")).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, collected)
+}
+
+func TestErrStreamTerminalsTwice(t *testing.T) {
+	s := Lines(strings.NewReader("a\nb\n"))
+	collected, err := s.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, collected)
+
+	// the stream already ran, a second terminal sees no element
+	assert.True(t, s.ToStream().ran.Load())
+	cnt, err := s.Count()
+	assert.NoError(t, err)
+	assert.Zero(t, cnt)
+}
+
+func TestLinesReadError(t *testing.T) {
+	boom := errors.New("boom")
+	s := Lines(iotest.TimeoutReader(strings.NewReader("a\nb\n")))
+	_, err := s.Collect()
+	assert.ErrorIs(t, err, iotest.ErrTimeout)
+
+	_, err = Lines(iotest.ErrReader(boom)).Collect()
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestFromScanner(t *testing.T) {
+	sc := bufio.NewScanner(strings.NewReader("one two  three"))
+	sc.Split(bufio.ScanWords)
+	collected, err := FromScanner(sc).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "three"}, collected)
+}
+
+func TestFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("1\n2\n3\n4\n5\n"), 0o600))
+
+	collected, err := FromFile(path).
+		Filter(func(s string) bool {
+			return s != "2"
+		}).
+		Limit(2).
+		Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, collected)
+
+	first := FromFile(path).FindFirst()
+	assert.Equal(t, "1", *first)
+}
+
+// countingFile counts the bytes read from it and whether it was closed.
+type countingFile struct {
+	r      io.Reader
+	read   atomic.Int64
+	closed atomic.Bool
+}
+
+func (f *countingFile) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	f.read.Add(int64(n))
+	return n, err
+}
+
+func (f *countingFile) Close() error {
+	f.closed.Store(true)
+	return nil
+}
+
+func TestScanStopsAfterShortCircuit(t *testing.T) {
+	content := strings.Repeat("line\n", 1_000_000)
+	f := &countingFile{r: strings.NewReader(content)}
+	s := scan("FromFile", func() (*bufio.Scanner, io.Closer, error) {
+		return bufio.NewScanner(f), f, nil
+	})
+	assert.Equal(t, "line", *s.FindFirst())
+
+	assert.Eventually(t, f.closed.Load, time.Second, time.Millisecond)
+	assert.Less(t, f.read.Load(), int64(len(content)))
+}
+
+func TestFromFileMissing(t *testing.T) {
+	cnt, err := FromFile(filepath.Join(t.TempDir(), "missing.txt")).Count()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Zero(t, cnt)
+}
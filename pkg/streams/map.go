@@ -3,6 +3,7 @@ package streams
 import (
 	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 )
 
@@ -24,18 +25,11 @@ func (t MapEntry[K, V]) String() string {
 }
 
 func MNew[K comparable, V any](data map[K]V) *Stream[MapEntry[K, V]] {
-	ch := make(chan MapEntry[K, V])
-	return &Stream[MapEntry[K, V]]{
-		data: ch,
-		run: func() {
-			func(tasks map[K]V) {
-				defer close(ch)
-				for k, v := range tasks {
-					ch <- MapEntry[K, V]{k, v}
-				}
-			}(data)
-		},
-	}
+	return source("MNew", func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		for k, v := range data {
+			st.send(MapEntry[K, V]{k, v})
+		}
+	}).withArgs(fmt.Sprintf("%d entries", len(data)))
 }
 
 func FromMap[K comparable, V any](data map[K]V) *Stream[MapEntry[K, V]] {
@@ -43,58 +37,71 @@ func FromMap[K comparable, V any](data map[K]V) *Stream[MapEntry[K, V]] {
 }
 
 func MNewKeys[K comparable, V any](data map[K]V) *Stream[K] {
-	ch := make(chan K)
-	return &Stream[K]{
-		data: ch,
-		run: func() {
-			func(tasks map[K]V) {
-				defer close(ch)
-				for k := range tasks {
-					ch <- k
-				}
-			}(data)
-		},
-	}
+	return source("MNewKeys", func(st *stage[K, K]) {
+		for k := range data {
+			st.send(k)
+		}
+	}).withArgs(fmt.Sprintf("%d entries", len(data)))
 }
 
 func MNewValues[K comparable, V any](data map[K]V) *Stream[V] {
-	ch := make(chan V)
-	return &Stream[V]{
-		data: ch,
-		run: func() {
-			func(tasks map[K]V) {
-				defer close(ch)
-				for _, v := range tasks {
-					ch <- v
-				}
-			}(data)
-		},
-	}
+	return source("MNewValues", func(st *stage[V, V]) {
+		for _, v := range data {
+			st.send(v)
+		}
+	}).withArgs(fmt.Sprintf("%d entries", len(data)))
 }
 
 func MCollect[K comparable, V any](stream *Stream[MapEntry[K, V]]) map[K]V {
-	stream.Run()
 	result := make(map[K]V)
-	for t := range stream.data {
+	consume(stream, "MCollect", func(t MapEntry[K, V]) bool {
 		result[t.K] = t.V
-	}
+		return true
+	})
 	return result
 }
 
 func MSorted[K cmp.Ordered, V any](s *Stream[MapEntry[K, V]]) *Stream[MapEntry[K, V]] {
-	ch := make(chan MapEntry[K, V])
-	return &Stream[MapEntry[K, V]]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			result := Collect(s)
-			sort.Slice(result, func(i, j int) bool {
-				return result[i].K < result[j].K
-			})
-			for _, r := range result {
-				ch <- r
-			}
-		},
+	if sort := s.rewrite().sort; sort != nil {
+		return sort(ASC)
 	}
+	sorted := derive(s, "MSorted", func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		var result []MapEntry[K, V]
+		st.each(func(t MapEntry[K, V]) bool {
+			result = append(result, t)
+			return true
+		})
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].K < result[j].K
+		})
+		for _, r := range result {
+			st.send(r)
+		}
+	})
+	sorted.opt = &rewrites[MapEntry[K, V]]{sort: ToMOrderedStream(s).Sorted}
+	return sorted
+}
+
+// MNewSorted streams the entries of data in ascending key order.
+func MNewSorted[K cmp.Ordered, V any](data map[K]V) *Stream[MapEntry[K, V]] {
+	return newSorted("MNewSorted", data, cmp.Compare[K])
+}
+
+// MNewSortedFunc streams the entries of data ordered by compare, which follows
+// the cmp.Compare convention.
+func MNewSortedFunc[K comparable, V any](data map[K]V, compare func(a, b K) int) *Stream[MapEntry[K, V]] {
+	return newSorted("MNewSortedFunc", data, compare)
+}
+
+func newSorted[K comparable, V any](name string, data map[K]V, compare func(a, b K) int) *Stream[MapEntry[K, V]] {
+	return source(name, func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		keys := make([]K, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, compare)
+		for _, k := range keys {
+			st.send(MapEntry[K, V]{k, data[k]})
+		}
+	}).withArgs(fmt.Sprintf("%d entries", len(data)))
 }
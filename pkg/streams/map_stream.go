@@ -0,0 +1,104 @@
+package streams
+
+import "sync/atomic"
+
+type MapStream[K comparable, V any] struct {
+	Stream[MapEntry[K, V]]
+}
+
+func ToMapStream[K comparable, V any](s *Stream[MapEntry[K, V]]) *MapStream[K, V] {
+	return &MapStream[K, V]{
+		Stream: Stream[MapEntry[K, V]]{
+			data: s.data,
+			run:  s.run,
+			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
+		},
+	}
+}
+
+func (s *MapStream[K, V]) ToStream() *Stream[MapEntry[K, V]] {
+	return &s.Stream
+}
+
+func (s *MapStream[K, V]) Filter(f FilterFun[MapEntry[K, V]]) *MapStream[K, V] {
+	return &MapStream[K, V]{
+		Stream: *s.ToStream().Filter(f),
+	}
+}
+
+func (s *MapStream[K, V]) FilterKeys(f FilterFun[K]) *MapStream[K, V] {
+	return s.Filter(func(e MapEntry[K, V]) bool {
+		return f(e.K)
+	})
+}
+
+func (s *MapStream[K, V]) FilterValues(f FilterFun[V]) *MapStream[K, V] {
+	return s.Filter(func(e MapEntry[K, V]) bool {
+		return f(e.V)
+	})
+}
+
+func (s *MapStream[K, V]) MapValues(mapper UnaryMapFun[V]) *MapStream[K, V] {
+	return MapValues(s, MapFun[V, V](mapper))
+}
+
+func (s *MapStream[K, V]) MapKeys(mapper UnaryMapFun[K]) *MapStream[K, V] {
+	return MapKeys(s, MapFun[K, K](mapper))
+}
+
+func (s *MapStream[K, V]) Peek(f func(MapEntry[K, V])) *MapStream[K, V] {
+	return &MapStream[K, V]{
+		Stream: *s.ToStream().Peek(f),
+	}
+}
+
+func (s *MapStream[K, V]) Limit(limit int) *MapStream[K, V] {
+	return &MapStream[K, V]{
+		Stream: *s.ToStream().Limit(limit),
+	}
+}
+
+func (s *MapStream[K, V]) Skip(skip int) *MapStream[K, V] {
+	return &MapStream[K, V]{
+		Stream: *s.ToStream().Skip(skip),
+	}
+}
+
+func (s *MapStream[K, V]) Keys() *Stream[K] {
+	return Map(s.ToStream(), func(e MapEntry[K, V]) K {
+		return e.K
+	})
+}
+
+func (s *MapStream[K, V]) Values() *Stream[V] {
+	return Map(s.ToStream(), func(e MapEntry[K, V]) V {
+		return e.V
+	})
+}
+
+// ToMap collects the stream into a map, the last value of a duplicate key wins.
+func (s *MapStream[K, V]) ToMap() map[K]V {
+	return MCollect(s.ToStream())
+}
+
+func MapValues[K comparable, V, R any](s *MapStream[K, V], mapper MapFun[V, R]) *MapStream[K, R] {
+	return ToMapStream(Map(s.ToStream(), func(e MapEntry[K, V]) MapEntry[K, R] {
+		return MapEntry[K, R]{e.K, mapper(e.V)}
+	}))
+}
+
+func MapKeys[K, R comparable, V any](s *MapStream[K, V], mapper MapFun[K, R]) *MapStream[R, V] {
+	return ToMapStream(Map(s.ToStream(), func(e MapEntry[K, V]) MapEntry[R, V] {
+		return MapEntry[R, V]{mapper(e.K), e.V}
+	}))
+}
+
+// Invert swaps the keys and values of the entries.
+func Invert[K, V comparable](s *MapStream[K, V]) *MapStream[V, K] {
+	return ToMapStream(Map(s.ToStream(), func(e MapEntry[K, V]) MapEntry[V, K] {
+		return MapEntry[V, K]{e.V, e.K}
+	}))
+}
@@ -0,0 +1,54 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapStreamChaining(t *testing.T) {
+	collected := ToMapStream(MNew(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})).
+		FilterKeys(func(k string) bool {
+			return k != "a"
+		}).
+		FilterValues(func(v int) bool {
+			return v%2 == 0
+		}).
+		MapKeys(strings.ToUpper).
+		MapValues(func(v int) int {
+			return v * 10
+		}).
+		ToMap()
+	assert.Equal(t, map[string]int{"B": 20, "D": 40}, collected)
+}
+
+func TestMapStreamTypeChanging(t *testing.T) {
+	s := ToMapStream(MNew(map[string]int{"a": 1, "bb": 2}))
+	lengths := MapKeys(s, func(k string) int {
+		return len(k)
+	})
+	collected := MapValues(lengths, func(v int) string {
+		return strings.Repeat("x", v)
+	}).ToMap()
+	assert.Equal(t, map[int]string{1: "x", 2: "xx"}, collected)
+}
+
+func TestMapStreamInvert(t *testing.T) {
+	collected := Invert(ToMapStream(MNew(map[string]int{"one": 1, "two": 2}))).ToMap()
+	assert.Equal(t, map[int]string{1: "one", 2: "two"}, collected)
+}
+
+func TestMapStreamKeysValues(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	assert.Equal(t, []string{"a", "b", "c"}, Collect(Sorted(ToMapStream(MNew(m)).Keys(), ASC)))
+	assert.Equal(t, []int{1, 2, 3}, Collect(Sorted(ToMapStream(MNew(m)).Values(), ASC)))
+}
+
+func TestMapStreamTerminalsTwice(t *testing.T) {
+	s := ToMapStream(MNew(map[string]int{"a": 1}))
+	assert.Equal(t, map[string]int{"a": 1}, s.ToMap())
+	// the stream already ran, a second terminal sees no element
+	assert.True(t, s.ToStream().ran.Load())
+	assert.Empty(t, s.ToMap())
+}
@@ -0,0 +1,114 @@
+package streams
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StageStats are the measurements of one stage of an observed pipeline. ID is
+// the position of the stage in the chain, sources first, as shown by Explain.
+type StageStats struct {
+	ID   int
+	Name string
+	// Source is set for the stages producing elements rather than reading
+	// them from upstream, such as New or each input of a join.
+	Source bool
+	// In and Out count the elements received from upstream and sent
+	// downstream.
+	In  int64
+	Out int64
+	// Busy is the time spent processing elements, in user functions mostly.
+	Busy time.Duration
+	// RecvWait is the time spent waiting for upstream, SendWait the time spent
+	// waiting for downstream.
+	RecvWait time.Duration
+	SendWait time.Duration
+	Elapsed  time.Duration
+}
+
+// Throughput is the number of elements handled per second, counting the
+// output of sources and the input of any other stage.
+func (s StageStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	n := s.In
+	if s.Source {
+		n = s.Out
+	}
+	return float64(n) / s.Elapsed.Seconds()
+}
+
+func (s StageStats) String() string {
+	return fmt.Sprintf("#%d %s in=%d out=%d busy=%s recv_wait=%s send_wait=%s elapsed=%s throughput=%.1f/s",
+		s.ID, s.Name, s.In, s.Out, s.Busy, s.RecvWait, s.SendWait, s.Elapsed, s.Throughput())
+}
+
+// Observer receives the measurements of every stage of an observed pipeline
+// once the stage is done. It is called from the stage goroutines. Adapters to
+// metrics systems such as Prometheus or expvar implement it.
+type Observer interface {
+	StageDone(stats StageStats)
+}
+
+// WithObserver instruments every stage of the pipeline s belongs to, the ones
+// before s as well as the ones chained after it. It must be called before the
+// stream runs.
+func (s *Stream[T]) WithObserver(obs Observer) *Stream[T] {
+	s.pipeline().setObserver(obs)
+	return s
+}
+
+// MetricsRecorder is an Observer keeping the measurements in memory.
+type MetricsRecorder struct {
+	mu    sync.Mutex
+	stats []StageStats
+}
+
+func NewMetricsRecorder() *MetricsRecorder {
+	return &MetricsRecorder{}
+}
+
+func (r *MetricsRecorder) StageDone(stats StageStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = append(r.stats, stats)
+}
+
+// Stats returns the measurements recorded so far ordered by stage.
+func (r *MetricsRecorder) Stats() []StageStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := append([]StageStats(nil), r.stats...)
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
+
+// Slowest returns the stage that was busy the longest.
+func (r *MetricsRecorder) Slowest() (StageStats, bool) {
+	stats := r.Stats()
+	if len(stats) == 0 {
+		return StageStats{}, false
+	}
+	slowest := stats[0]
+	for _, s := range stats[1:] {
+		if s.Busy > slowest.Busy {
+			slowest = s
+		}
+	}
+	return slowest, true
+}
+
+func (r *MetricsRecorder) String() string {
+	var b strings.Builder
+	for _, s := range r.Stats() {
+		b.WriteString(s.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
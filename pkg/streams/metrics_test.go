@@ -0,0 +1,95 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithObserver(t *testing.T) {
+	recorder := NewMetricsRecorder()
+	s := New(1, 2, 3, 4, 5).
+		Filter(func(i int) bool {
+			return i%2 == 0
+		}).
+		WithObserver(recorder)
+	collected := Collect(Map(s, func(i int) int {
+		time.Sleep(10 * time.Millisecond)
+		return i * 10
+	}))
+	assert.Equal(t, []int{20, 40}, collected)
+
+	// Filter and Map are fused into a single stage
+	stats := recorder.Stats()
+	assert.Len(t, stats, 3)
+	assert.Equal(t, []string{"New", "Filter+Map", "Collect"}, []string{stats[0].Name, stats[1].Name, stats[2].Name})
+	assert.Equal(t, []int{0, 1, 2}, []int{stats[0].ID, stats[1].ID, stats[2].ID})
+	assert.Equal(t, int64(5), stats[0].Out)
+	assert.Equal(t, int64(5), stats[1].In)
+	assert.Equal(t, int64(2), stats[1].Out)
+	assert.Equal(t, int64(2), stats[2].In)
+	assert.GreaterOrEqual(t, stats[1].Busy, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, stats[2].RecvWait, 20*time.Millisecond)
+	assert.Greater(t, stats[2].Throughput(), 0.0)
+
+	slowest, ok := recorder.Slowest()
+	assert.True(t, ok)
+	assert.Equal(t, "Filter+Map", slowest.Name)
+	assert.Contains(t, recorder.String(), "#1 Filter+Map in=5 out=2")
+}
+
+func TestWithObserverShortCircuit(t *testing.T) {
+	recorder := NewMetricsRecorder()
+	first := New(1, 2, 3, 4, 5).WithObserver(recorder).Limit(2).FindFirst()
+	assert.Equal(t, 1, *first)
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.Stats()) == 3
+	}, time.Second, time.Millisecond)
+	stats := recorder.Stats()
+	assert.Equal(t, "Limit", stats[1].Name)
+	// FindFirst stopped Limit, which dropped the second element
+	assert.Equal(t, int64(1), stats[1].Out)
+	assert.Equal(t, "FindFirst", stats[2].Name)
+	assert.Equal(t, int64(1), stats[2].In)
+}
+
+func TestWithObserverJoin(t *testing.T) {
+	recorder := NewMetricsRecorder()
+	left := New(1, 2, 3).WithObserver(recorder)
+	joined := SemiJoin(left, New(1), func(i int) int {
+		return i
+	}, func(i int) int {
+		return i
+	})
+	timed := Timeout(joined, time.Second, &testClock{})
+	collected, err := timed.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, collected)
+
+	stats := recorder.Stats()
+	if assert.Len(t, stats, 5) {
+		// both inputs of the join are sources
+		for _, source := range stats[:2] {
+			assert.Equal(t, "New", source.Name)
+			assert.True(t, source.Source)
+			assert.Greater(t, source.Throughput(), 0.0)
+		}
+		assert.Equal(t, "SemiJoin", stats[2].Name)
+		assert.False(t, stats[2].Source)
+		assert.Equal(t, int64(4), stats[2].In)
+		assert.Equal(t, "Timeout", stats[3].Name)
+		assert.Equal(t, int64(1), stats[3].In)
+	}
+}
+
+func TestWithoutObserver(t *testing.T) {
+	recorder := NewMetricsRecorder()
+	Collect(New(1, 2, 3).Filter(func(int) bool {
+		return true
+	}))
+	_, ok := recorder.Slowest()
+	assert.False(t, ok)
+	assert.Empty(t, recorder.String())
+}
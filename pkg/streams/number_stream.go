@@ -16,15 +16,14 @@ func ToNumberStream[T constraints.Integer | constraints.Float](s *Stream[T]) *Nu
 			data: s.data,
 			run:  s.run,
 			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
 		},
 	}
 }
 func (s *NumberStream[T]) Sum() (result T) {
-	s.Run()
-	for t := range s.data {
-		result += t
-	}
-	return result
+	return Sum(s.ToStream())
 }
 func (s *NumberStream[T]) Peek(f func(T)) *NumberStream[T] {
 	return &NumberStream[T]{
@@ -69,49 +68,102 @@ func (s *NumberStream[T]) TakeWhile(f func(T) bool) *NumberStream[T] {
 	}
 }
 
-func (s *NumberStream[T]) ToStream() *Stream[T] {
-	return &Stream[T]{
-		data: s.data,
-		run:  s.run,
+func (s *NumberStream[T]) Sorted(order SortOrder) *NumberStream[T] {
+	return &NumberStream[T]{
+		Stream: *Sorted(s.ToStream(), order),
 	}
 }
 
+func (s *NumberStream[T]) Distinct() *NumberStream[T] {
+	return &NumberStream[T]{
+		Stream: *Distinct(s.ToStream()),
+	}
+}
+
+// CumulativeSum passes downstream the running totals of s.
+func (s *NumberStream[T]) CumulativeSum() *NumberStream[T] {
+	return ToNumberStream(fold(s.ToStream(), "CumulativeSum", 0, func(sum T, t T) T {
+		return sum + t
+	}))
+}
+
+// RunningAverage passes downstream the average of the last window elements of
+// s, or of the elements seen so far while there are fewer. A non positive
+// window averages every element seen so far.
+func (s *NumberStream[T]) RunningAverage(window int) *NumberStream[float64] {
+	return ToNumberStream(fold(s.ToStream(), "RunningAverage", 0, newMovingAverage[T](window).add).withArgs(window))
+}
+
+type movingAverage[T constraints.Integer | constraints.Float] struct {
+	window []T
+	next   int
+	sum    float64
+	count  int
+}
+
+func newMovingAverage[T constraints.Integer | constraints.Float](window int) *movingAverage[T] {
+	m := &movingAverage[T]{}
+	if window > 0 {
+		m.window = make([]T, 0, window)
+	}
+	return m
+}
+
+func (m *movingAverage[T]) add(_ float64, t T) float64 {
+	m.sum += float64(t)
+	if m.window == nil {
+		m.count++
+		return m.sum / float64(m.count)
+	}
+	if len(m.window) < cap(m.window) {
+		m.window = append(m.window, t)
+	} else {
+		m.sum -= float64(m.window[m.next])
+		m.window[m.next] = t
+		m.next = (m.next + 1) % len(m.window)
+	}
+	return m.sum / float64(len(m.window))
+}
+
+func (s *NumberStream[T]) ToStream() *Stream[T] {
+	return &s.Stream
+}
+
+// ToOrderedStream converts s, keeping its elements and the operations chained
+// so far.
+func (s *NumberStream[T]) ToOrderedStream() *OrderedStream[T] {
+	return ToOrderedStream(s.ToStream())
+}
+
+// ToComparableStream converts s like ToOrderedStream does.
+func (s *NumberStream[T]) ToComparableStream() *ComparableStream[T] {
+	return ToComparableStream(s.ToStream())
+}
+
 func (s *NumberStream[T]) Average() (result float64) {
-	s.Run()
 	var count int
-	for t := range s.data {
+	consume(s.ToStream(), "Average", func(t T) bool {
 		result += float64(t)
 		count++
-	}
+		return true
+	})
 	if count == 0 {
 		return 0
 	}
 	return result / float64(count)
 }
 func (s *NumberStream[T]) Max() (result *T) {
-	s.Run()
-	for t := range s.data {
-		if result == nil || t > *result {
-			result = &t
-		}
-	}
-	return result
+	return Max(s.ToStream())
 }
 
 func (s *NumberStream[T]) Min() (result *T) {
-	s.Run()
-	for t := range s.data {
-		if result == nil || t < *result {
-			result = &t
-		}
-	}
-	return result
+	return Min(s.ToStream())
 }
 
 func (s *NumberStream[T]) Count() (result int64) {
-	s.Run()
-	for range s.data {
-		result++
-	}
-	return result
+	return Count(s.ToStream())
+}
+
+func (s *NumberStream[T]) CollectToSet() map[T]struct{} {
+	return CollectToSet(s.ToStream())
 }
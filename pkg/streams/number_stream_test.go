@@ -1,6 +1,9 @@
 package streams
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestNumberStream_Average(t *testing.T) {
 	s := New(1, 2, 3, 4, 5)
@@ -17,6 +20,28 @@ func TestNumberStream_AverageEmpty(t *testing.T) {
 	}
 }
 
+func TestNumberStream_TerminalsTwice(t *testing.T) {
+	ns := ToNumberStream(New(1, 2, 3))
+	if ns.Sum() != 6 {
+		t.Fail()
+	}
+	// the stream already ran, a second terminal sees no element instead of
+	// running it again
+	if !ns.ToStream().ran.Load() || ns.Sum() != 0 || ns.Max() != nil {
+		t.Fail()
+	}
+
+	ords := ToOrderedStream(New(1, 2, 3))
+	if *ords.Max() != 3 || ords.Min() != nil {
+		t.Fail()
+	}
+
+	cs := ToComparableStream(New(1, 1, 2))
+	if len(cs.CollectToSet()) != 2 || len(cs.CollectToSet()) != 0 {
+		t.Fail()
+	}
+}
+
 func TestNumberStream_Count(t *testing.T) {
 	s := New(1, 2, 3, 4, 5)
 	ns := ToNumberStream(s)
@@ -48,3 +73,46 @@ func TestNumberStream_Sum(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNumberStream_Chain(t *testing.T) {
+	ns := ToNumberStream(New(4, 1, 3, 1, 5, 9, 2, 6)).
+		Distinct().
+		Sorted(DESC).
+		TakeWhile(func(i int) bool {
+			return i > 2
+		})
+	if ns.Sum() != 27 {
+		t.Fail()
+	}
+}
+
+func TestNumberStream_ToOrderedStream(t *testing.T) {
+	smallest := ToNumberStream(New(3.5, 1.5, 2.5)).Map(func(f float64) float64 {
+		return f * 2
+	}).ToOrderedStream().Min()
+	if *smallest != 3 {
+		t.Fail()
+	}
+}
+
+func TestNumberStream_CumulativeSum(t *testing.T) {
+	sums := ToNumberStream(New(1, 2, 3, 4)).CumulativeSum().Collect()
+	if !reflect.DeepEqual(sums, []int{1, 3, 6, 10}) {
+		t.Errorf("Expected [1 3 6 10] but got %v", sums)
+	}
+	sums = ToNumberStream(New(1, 2, 3, 4)).CumulativeSum().Limit(2).Collect()
+	if !reflect.DeepEqual(sums, []int{1, 3}) {
+		t.Errorf("Expected [1 3] but got %v", sums)
+	}
+}
+
+func TestNumberStream_RunningAverage(t *testing.T) {
+	averages := ToNumberStream(New(2, 4, 6, 8, 10)).RunningAverage(2).Collect()
+	if !reflect.DeepEqual(averages, []float64{2, 3, 5, 7, 9}) {
+		t.Errorf("Expected [2 3 5 7 9] but got %v", averages)
+	}
+	averages = ToNumberStream(New(2, 4, 6, 8, 10)).RunningAverage(0).Collect()
+	if !reflect.DeepEqual(averages, []float64{2, 3, 4, 5, 6}) {
+		t.Errorf("Expected [2 3 4 5 6] but got %v", averages)
+	}
+}
@@ -0,0 +1,100 @@
+package streams
+
+// The plan of a stream is optimized as operators are chained, which is safe as
+// long as the stream being chained from did not run:
+//
+//   - element-wise operators (Filter, Map and Peek) following one another run
+//     in a single stage, saving a goroutine and a channel hop per element,
+//   - a Limit following a Map is moved before it, so that the mapper is not
+//     called for elements that are dropped anyway,
+//   - a sort following a sort replaces it,
+//   - a Skip following a Skip is merged with it.
+
+// rewrites holds how the operator that produced a stream can be merged with or
+// reordered around the operator chained next.
+type rewrites[T any] struct {
+	// chain runs the fused element-wise operators in the goroutine of the
+	// stage measured by m, feeding their output to emit until it returns
+	// false.
+	chain func(m *meter, emit func(T) bool)
+	// limit applies a Limit before the operator.
+	limit func(n int) *Stream[T]
+	// skip merges a Skip into the operator.
+	skip func(n int) *Stream[T]
+	// sort replaces the operator, a sort, with a sort in the given order.
+	sort func(order SortOrder) *Stream[T]
+}
+
+// rewrite returns the rewrites of s when it can still be rewritten.
+func (s *Stream[T]) rewrite() *rewrites[T] {
+	if s.opt == nil || s.ran.Load() {
+		return &rewrites[T]{}
+	}
+	return s.opt
+}
+
+// fuse returns the stream produced by the element-wise operator name, where
+// step maps every element to at most one element. It runs in the same stage as
+// the element-wise operators s was produced by, if any.
+func fuse[T, R any](s *Stream[T], name string, step func(T) (R, bool)) *Stream[R] {
+	return fuseStep(s, name, func() func(T) (R, bool) {
+		return step
+	})
+}
+
+// fuseStep is like fuse for operators keeping state across elements, such as
+// a position: newStep makes the step once the stage runs.
+func fuseStep[T, R any](s *Stream[T], name string, newStep func() func(T) (R, bool)) *Stream[R] {
+	var chain func(m *meter, emit func(R) bool)
+	var n *planNode
+	if upstream := s.rewrite().chain; upstream != nil {
+		chain = func(m *meter, emit func(R) bool) {
+			step := newStep()
+			upstream(m, func(t T) bool {
+				if r, ok := step(t); ok {
+					return emit(r)
+				}
+				return true
+			})
+		}
+		n = newPlanNode(s.node.name+"+"+name, s.node.inputs...)
+	} else {
+		chain = func(m *meter, emit func(R) bool) {
+			step := newStep()
+			s.Run()
+			in := &stage[T, T]{meter: m, from: s.node, in: s.data}
+			done := false
+			defer func() {
+				// a step panicked, stop the upstream stages
+				if !done {
+					stopStream(s.node, s.data)
+				}
+			}()
+			in.each(func(t T) bool {
+				if r, ok := step(t); ok {
+					return emit(r)
+				}
+				return true
+			})
+			done = true
+		}
+		n = newPlanNode(name, s.node)
+	}
+	ch := make(chan R)
+	p := s.pipeline()
+	return &Stream[R]{
+		data: ch,
+		pipe: p,
+		node: n,
+		opt:  &rewrites[R]{chain: chain},
+		run: func() {
+			defer close(ch)
+			st := newStage[R, R](p, n, nil, nil, ch)
+			defer st.done()
+			defer recoverStage[R](n, nil, nil)
+			chain(st.meter, func(r R) bool {
+				return st.send(r)
+			})
+		},
+	}
+}
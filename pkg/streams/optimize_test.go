@@ -0,0 +1,93 @@
+package streams
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFusion(t *testing.T) {
+	var peeked []int
+	s := New(1, 2, 3, 4, 5, 6).
+		Filter(func(i int) bool {
+			return i%2 == 0
+		}).
+		Peek(func(i int) {
+			peeked = append(peeked, i)
+		})
+	mapped := Map(s, func(i int) string {
+		return string(rune('a' + i))
+	})
+	assert.Equal(t, "#1 Filter+Peek+Map\n"+
+		"└─ #0 New(6 elements)\n", mapped.Explain())
+	assert.Equal(t, []string{"c", "e", "g"}, mapped.Collect())
+	assert.Equal(t, []int{2, 4, 6}, peeked)
+}
+
+func TestFusionAfterRun(t *testing.T) {
+	s := New(1, 2, 3).Filter(func(i int) bool {
+		return i > 1
+	})
+	s.Run()
+	mapped := s.Map(func(i int) int {
+		return i * 10
+	})
+	assert.Equal(t, "#2 Map\n"+
+		"└─ #1 Filter\n"+
+		"   └─ #0 New(3 elements)\n", mapped.Explain())
+	assert.Equal(t, []int{20, 30}, mapped.Collect())
+}
+
+func TestLimitPushedThroughMap(t *testing.T) {
+	var calls atomic.Int32
+	s := New(1, 2, 3, 4, 5).Map(func(i int) int {
+		calls.Add(1)
+		return i * i
+	}).Limit(2)
+	assert.Equal(t, "#2 Map\n"+
+		"└─ #1 Limit(2) [short-circuit]\n"+
+		"   └─ #0 New(5 elements)\n", s.Explain())
+	assert.Equal(t, []int{1, 4}, s.Collect())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestLimitNotPushedThroughFilter(t *testing.T) {
+	s := New(1, 2, 3, 4, 5).
+		Filter(func(i int) bool {
+			return i > 2
+		}).
+		Map(func(i int) int {
+			return -i
+		}).
+		Limit(2)
+	assert.Equal(t, "#3 Map\n"+
+		"└─ #2 Limit(2) [short-circuit]\n"+
+		"   └─ #1 Filter\n"+
+		"      └─ #0 New(5 elements)\n", s.Explain())
+	assert.Equal(t, []int{-3, -4}, s.Collect())
+}
+
+func TestSortedAfterSorted(t *testing.T) {
+	s := Sorted(Sorted(New(3, 1, 2), ASC), DESC)
+	assert.Equal(t, "#1 Sorted(DESC) [stateful]\n"+
+		"└─ #0 New(3 elements)\n", s.Explain())
+	assert.Equal(t, []int{3, 2, 1}, s.Collect())
+
+	m := ToMOrderedStream(MSorted(MNew(map[string]int{"b": 2, "a": 1, "c": 3}))).Sorted(DESC)
+	assert.Equal(t, "#1 Sorted(DESC) [stateful]\n"+
+		"└─ #0 MNew(3 entries)\n", m.Explain())
+	assert.Equal(t, []MapEntry[string, int]{{"c", 3}, {"b", 2}, {"a", 1}}, m.Collect())
+}
+
+func TestSkipAfterSkip(t *testing.T) {
+	s := New(1, 2, 3, 4, 5, 6).Skip(1).Skip(2)
+	assert.Equal(t, "#1 Skip(3)\n"+
+		"└─ #0 New(6 elements)\n", s.Explain())
+	assert.Equal(t, []int{4, 5, 6}, s.Collect())
+
+	s = New(1, 2, 3, 4, 5).Skip(-1).Skip(2)
+	assert.Equal(t, "#1 Skip(2)\n"+
+		"└─ #0 New(5 elements)\n", s.Explain())
+	assert.Equal(t, []int{3, 4, 5}, s.Collect())
+}
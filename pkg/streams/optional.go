@@ -0,0 +1,173 @@
+package streams
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Optional holds a value or nothing, as returned by the terminal operations
+// that have no result on an empty stream.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+func OptionalOf[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+func EmptyOptional[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// OptionalOfPtr returns the Optional holding *p, or an empty one when p is
+// nil, to convert the results of FindFirst, Min and the like.
+func OptionalOfPtr[T any](p *T) Optional[T] {
+	if p == nil {
+		return Optional[T]{}
+	}
+	return OptionalOf(*p)
+}
+
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+func (o Optional[T]) IsEmpty() bool {
+	return !o.present
+}
+
+// Get returns the value and whether there is one.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Ptr returns a pointer to a copy of the value, or nil when there is none.
+func (o Optional[T]) Ptr() *T {
+	if !o.present {
+		return nil
+	}
+	v := o.value
+	return &v
+}
+
+func (o Optional[T]) OrElse(other T) T {
+	if o.present {
+		return o.value
+	}
+	return other
+}
+
+func (o Optional[T]) OrElseGet(f func() T) T {
+	if o.present {
+		return o.value
+	}
+	return f()
+}
+
+// Map returns the Optional holding the mapped value, MapOptional maps to
+// another type.
+func (o Optional[T]) Map(mapper UnaryMapFun[T]) Optional[T] {
+	return MapOptional(o, MapFun[T, T](mapper))
+}
+
+func (o Optional[T]) Filter(filter FilterFun[T]) Optional[T] {
+	if o.present && filter(o.value) {
+		return o
+	}
+	return Optional[T]{}
+}
+
+func (o Optional[T]) IfPresent(f func(T)) {
+	if o.present {
+		f(o.value)
+	}
+}
+
+func (o Optional[T]) IfPresentOrElse(f func(T), orElse func()) {
+	if o.present {
+		f(o.value)
+	} else {
+		orElse()
+	}
+}
+
+func (o Optional[T]) String() string {
+	if !o.present {
+		return "Optional.empty"
+	}
+	return fmt.Sprintf("Optional[%v]", o.value)
+}
+
+func MapOptional[T, R any](o Optional[T], mapper MapFun[T, R]) Optional[R] {
+	if !o.present {
+		return Optional[R]{}
+	}
+	return OptionalOf(mapper(o.value))
+}
+
+func (s *Stream[T]) FindFirstOptional() Optional[T] {
+	return OptionalOfPtr(FindFirst(s))
+}
+
+// FindAny returns any element of s. Streams are sequential, so it is the first
+// one for now, but callers must not rely on it.
+func (s *Stream[T]) FindAny() Optional[T] {
+	var found Optional[T]
+	consume(s, "FindAny", func(t T) bool {
+		found = OptionalOf(t)
+		return false
+	})
+	return found
+}
+
+// ReduceOptional folds the elements of s with f, the first element being the
+// initial accumulator. It is empty when s is.
+func (s *Stream[T]) ReduceOptional(f func(acc T, t T) T) Optional[T] {
+	return ReduceOptional(s, f)
+}
+
+func FindFirstOptional[T any](s *Stream[T]) Optional[T] {
+	return s.FindFirstOptional()
+}
+
+func FindAny[T any](s *Stream[T]) Optional[T] {
+	return s.FindAny()
+}
+
+func ReduceOptional[T any](s *Stream[T], f func(acc T, t T) T) Optional[T] {
+	var result Optional[T]
+	consume(s, "ReduceOptional", func(t T) bool {
+		if result.present {
+			result.value = f(result.value, t)
+		} else {
+			result = OptionalOf(t)
+		}
+		return true
+	})
+	return result
+}
+
+func MinOptional[T cmp.Ordered](s *Stream[T]) Optional[T] {
+	return OptionalOfPtr(Min(s))
+}
+
+func MaxOptional[T cmp.Ordered](s *Stream[T]) Optional[T] {
+	return OptionalOfPtr(Max(s))
+}
+
+func (s *OrderedStream[T]) MinOptional() Optional[T] {
+	return MinOptional(s.ToStream())
+}
+
+func (s *OrderedStream[T]) MaxOptional() Optional[T] {
+	return MaxOptional(s.ToStream())
+}
+
+func (s *NumberStream[T]) MinOptional() Optional[T] {
+	return MinOptional(s.ToStream())
+}
+
+func (s *NumberStream[T]) MaxOptional() Optional[T] {
+	return MaxOptional(s.ToStream())
+}
@@ -0,0 +1,76 @@
+package streams
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	present := OptionalOf(21)
+	empty := EmptyOptional[int]()
+
+	assert.True(t, present.IsPresent())
+	assert.True(t, empty.IsEmpty())
+	v, ok := present.Get()
+	assert.True(t, ok)
+	assert.Equal(t, 21, v)
+	_, ok = empty.Get()
+	assert.False(t, ok)
+	assert.Equal(t, 21, *present.Ptr())
+	assert.Nil(t, empty.Ptr())
+
+	assert.Equal(t, 21, present.OrElse(0))
+	assert.Equal(t, 7, empty.OrElse(7))
+	assert.Equal(t, 8, empty.OrElseGet(func() int {
+		return 8
+	}))
+
+	double := func(i int) int {
+		return i * 2
+	}
+	assert.Equal(t, OptionalOf(42), present.Map(double))
+	assert.Equal(t, empty, empty.Map(double))
+	assert.Equal(t, OptionalOf("21"), MapOptional(present, strconv.Itoa))
+	assert.Equal(t, empty, present.Filter(func(i int) bool {
+		return i > 30
+	}))
+
+	var got []string
+	present.IfPresent(func(i int) {
+		got = append(got, "present "+strconv.Itoa(i))
+	})
+	empty.IfPresent(func(int) {
+		got = append(got, "unexpected")
+	})
+	empty.IfPresentOrElse(func(int) {
+		got = append(got, "unexpected")
+	}, func() {
+		got = append(got, "empty")
+	})
+	assert.Equal(t, []string{"present 21", "empty"}, got)
+
+	assert.Equal(t, "Optional[21]", present.String())
+	assert.Equal(t, "Optional.empty", empty.String())
+	assert.Equal(t, empty, OptionalOfPtr[int](nil))
+}
+
+func TestOptionalTerminals(t *testing.T) {
+	assert.Equal(t, OptionalOf(2), New(1, 2, 3).Skip(1).FindFirstOptional())
+	assert.Equal(t, EmptyOptional[int](), FindFirstOptional(New[int]()))
+	assert.True(t, New(1, 2, 3).FindAny().IsPresent())
+	assert.True(t, FindAny(New[string]()).IsEmpty())
+
+	sum := func(a, b int) int {
+		return a + b
+	}
+	assert.Equal(t, OptionalOf(6), New(1, 2, 3).ReduceOptional(sum))
+	assert.Equal(t, OptionalOf(5), ReduceOptional(New(5), sum))
+	assert.True(t, ReduceOptional(New[int](), sum).IsEmpty())
+
+	assert.Equal(t, OptionalOf(1), MinOptional(New(3, 1, 2)))
+	assert.Equal(t, OptionalOf("c"), ToOrderedStream(New("b", "c", "a")).MaxOptional())
+	assert.True(t, ToNumberStream(New[float64]()).MaxOptional().IsEmpty())
+	assert.Equal(t, 0.5, ToNumberStream(New(1.5, 0.5)).MinOptional().OrElse(-1))
+}
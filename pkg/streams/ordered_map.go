@@ -0,0 +1,61 @@
+package streams
+
+import "slices"
+
+// OrderedMap is a map remembering the order in which keys were first inserted,
+// so that streaming it is reproducible.
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		values: make(map[K]V),
+	}
+}
+
+// Set stores v under k. Updating an existing key keeps its position.
+func (m *OrderedMap[K, V]) Set(k K, v V) {
+	if _, ok := m.values[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.values[k] = v
+}
+
+func (m *OrderedMap[K, V]) Get(k K) (V, bool) {
+	v, ok := m.values[k]
+	return v, ok
+}
+
+func (m *OrderedMap[K, V]) Delete(k K) {
+	if _, ok := m.values[k]; !ok {
+		return
+	}
+	delete(m.values, k)
+	m.keys = slices.DeleteFunc(m.keys, func(key K) bool {
+		return key == k
+	})
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+func (m *OrderedMap[K, V]) Keys() []K {
+	return slices.Clone(m.keys)
+}
+
+// Stream streams the entries in insertion order.
+func (m *OrderedMap[K, V]) Stream() *Stream[MapEntry[K, V]] {
+	return source("OrderedMap", func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		for _, k := range m.keys {
+			st.send(MapEntry[K, V]{k, m.values[k]})
+		}
+	})
+}
+
+// MNewOrdered streams the entries of m in insertion order.
+func MNewOrdered[K comparable, V any](m *OrderedMap[K, V]) *Stream[MapEntry[K, V]] {
+	return m.Stream()
+}
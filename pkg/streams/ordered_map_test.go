@@ -0,0 +1,39 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMNewSorted(t *testing.T) {
+	s := MNewSorted(map[string]int{"b": 2, "c": 3, "a": 1})
+	assert.Equal(t, "#0 MNewSorted(3 entries)\n", s.Explain())
+	collected := Collect(s)
+	assert.Equal(t, []MapEntry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, collected)
+}
+
+func TestMNewSortedFunc(t *testing.T) {
+	collected := Collect(MNewSortedFunc(map[string]int{"b": 2, "c": 3, "a": 1}, func(a, b string) int {
+		return strings.Compare(b, a)
+	}))
+	assert.Equal(t, []MapEntry[string, int]{{"c", 3}, {"b", 2}, {"a", 1}}, collected)
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+	m.Delete("a")
+	m.Delete("missing")
+
+	v, ok := m.Get("z")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, []string{"z", "m"}, m.Keys())
+	assert.Equal(t, []MapEntry[string, int]{{"z", 4}, {"m", 3}}, Collect(MNewOrdered(m)))
+}
@@ -16,37 +16,91 @@ func ToOrderedStream[T cmp.Ordered](s *Stream[T]) *OrderedStream[T] {
 			data: s.data,
 			run:  s.run,
 			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
 		},
 	}
 }
 
-func (s *OrderedStream[T]) Sorted(order SortOrder) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			var data []T
-			for t := range s.data {
-				data = append(data, t)
-			}
-			if order == DESC {
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] > data[j]
-				})
-			} else {
-				sort.Slice(data, func(i, j int) bool {
-					return data[i] < data[j]
-				})
-			}
-			for _, t := range data {
-				ch <- t
-			}
-		},
+func (s *OrderedStream[T]) Sorted(order SortOrder) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *Sorted(s.ToStream(), order),
+	}
+}
+
+func (s *OrderedStream[T]) Distinct() *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *Distinct(s.ToStream()),
+	}
+}
+
+func (s *OrderedStream[T]) Peek(f func(T)) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Peek(f),
+	}
+}
+
+func (s *OrderedStream[T]) Filter(f FilterFun[T]) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Filter(f),
+	}
+}
+
+func (s *OrderedStream[T]) Limit(limit int) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Limit(limit),
 	}
 }
 
+func (s *OrderedStream[T]) Skip(skip int) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Skip(skip),
+	}
+}
+
+func (s *OrderedStream[T]) Reverse() *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Reverse(),
+	}
+}
+
+func (s *OrderedStream[T]) Map(mapper UnaryMapFun[T]) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().Map(mapper),
+	}
+}
+
+func (s *OrderedStream[T]) DropWhile(f func(T) bool) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().DropWhile(f),
+	}
+}
+
+func (s *OrderedStream[T]) TakeWhile(f func(T) bool) *OrderedStream[T] {
+	return &OrderedStream[T]{
+		Stream: *s.ToStream().TakeWhile(f),
+	}
+}
+
+func (s *OrderedStream[T]) Min() *T {
+	return Min(s.ToStream())
+}
+
+func (s *OrderedStream[T]) Max() *T {
+	return Max(s.ToStream())
+}
+
+func (s *OrderedStream[T]) ToStream() *Stream[T] {
+	return &s.Stream
+}
+
+// ToComparableStream converts s, keeping its elements and the operations
+// chained so far.
+func (s *OrderedStream[T]) ToComparableStream() *ComparableStream[T] {
+	return ToComparableStream(s.ToStream())
+}
+
 type MOrderedStream[K cmp.Ordered, V any] struct {
 	Stream[MapEntry[K, V]]
 }
@@ -57,33 +111,36 @@ func ToMOrderedStream[K cmp.Ordered, V any](s *Stream[MapEntry[K, V]]) *MOrdered
 			data: s.data,
 			run:  s.run,
 			ran:  atomic.Bool{},
+			pipe: s.pipe,
+			node: s.node,
+			opt:  s.opt,
 		},
 	}
 }
 
 func (s *MOrderedStream[K, V]) Sorted(order SortOrder) *Stream[MapEntry[K, V]] {
-	ch := make(chan MapEntry[K, V])
-	return &Stream[MapEntry[K, V]]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			var data []MapEntry[K, V]
-			for t := range s.data {
-				data = append(data, t)
-			}
-			if order == DESC {
-				sort.Slice(data, func(i, j int) bool {
-					return data[i].K > data[j].K
-				})
-			} else {
-				sort.Slice(data, func(i, j int) bool {
-					return data[i].K < data[j].K
-				})
-			}
-			for _, t := range data {
-				ch <- t
-			}
-		},
+	if sort := s.rewrite().sort; sort != nil {
+		return sort(order)
 	}
+	sorted := derive(&s.Stream, "Sorted", func(st *stage[MapEntry[K, V], MapEntry[K, V]]) {
+		var data []MapEntry[K, V]
+		st.each(func(t MapEntry[K, V]) bool {
+			data = append(data, t)
+			return true
+		})
+		if order == DESC {
+			sort.Slice(data, func(i, j int) bool {
+				return data[i].K > data[j].K
+			})
+		} else {
+			sort.Slice(data, func(i, j int) bool {
+				return data[i].K < data[j].K
+			})
+		}
+		for _, t := range data {
+			st.send(t)
+		}
+	}).withArgs(order)
+	sorted.opt = &rewrites[MapEntry[K, V]]{sort: s.Sorted}
+	return sorted
 }
@@ -0,0 +1,59 @@
+// Package otelstreams reports the stages of stream pipelines as OpenTelemetry
+// spans.
+package otelstreams
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vkumbhar94/go-streams/pkg/streams"
+)
+
+const (
+	StageIDKey  = attribute.Key("streams.stage.id")
+	StageKey    = attribute.Key("streams.stage.name")
+	InKey       = attribute.Key("streams.elements.in")
+	OutKey      = attribute.Key("streams.elements.out")
+	BusyKey     = attribute.Key("streams.busy_ms")
+	RecvWaitKey = attribute.Key("streams.recv_wait_ms")
+	SendWaitKey = attribute.Key("streams.send_wait_ms")
+)
+
+// Tracer is a streams.Tracer starting one span per stage as a child of the
+// span found in the context it was created with.
+type Tracer struct {
+	ctx    context.Context
+	tracer trace.Tracer
+}
+
+func NewTracer(ctx context.Context, tracer trace.Tracer) *Tracer {
+	return &Tracer{
+		ctx:    ctx,
+		tracer: tracer,
+	}
+}
+
+func (t *Tracer) StartStage(id int, name string) streams.StageSpan {
+	_, span := t.tracer.Start(t.ctx, "streams."+name, trace.WithAttributes(
+		StageIDKey.Int(id),
+		StageKey.String(name),
+	))
+	return stageSpan{span}
+}
+
+type stageSpan struct {
+	span trace.Span
+}
+
+func (s stageSpan) End(stats streams.StageStats) {
+	s.span.SetAttributes(
+		InKey.Int64(stats.In),
+		OutKey.Int64(stats.Out),
+		BusyKey.Int64(stats.Busy.Milliseconds()),
+		RecvWaitKey.Int64(stats.RecvWait.Milliseconds()),
+		SendWaitKey.Int64(stats.SendWait.Milliseconds()),
+	)
+	s.span.End()
+}
@@ -0,0 +1,48 @@
+package otelstreams
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/vkumbhar94/go-streams/pkg/streams"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "job")
+	s := streams.New(1, 2, 3, 4, 5).
+		WithTracer(NewTracer(ctx, tracer)).
+		Filter(func(i int) bool {
+			return i > 2
+		})
+	collected := streams.Collect(streams.Map(s, func(i int) int {
+		return i * 2
+	}))
+	parent.End()
+	assert.Equal(t, []int{6, 8, 10}, collected)
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	assert.Len(t, spans, 4)
+	for _, name := range []string{"streams.New", "streams.Filter+Map", "streams.Collect"} {
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[name].Parent.SpanID(), name)
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans["streams.Filter+Map"].Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, int64(1), attrs[StageIDKey].AsInt64())
+	assert.Equal(t, int64(5), attrs[InKey].AsInt64())
+	assert.Equal(t, int64(3), attrs[OutKey].AsInt64())
+}
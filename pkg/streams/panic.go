@@ -0,0 +1,23 @@
+package streams
+
+import "fmt"
+
+// PanicError is a panic recovered from a goroutine run by the library, raised
+// again on the goroutine calling the terminal operation so that it can be
+// recovered there. Stage is the operation whose callback panicked, Value the
+// value it panicked with and Stack the stack of the goroutine that panicked.
+type PanicError struct {
+	Stage string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("streams: panic in %s: %v\n\n%s", e.Stage, e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
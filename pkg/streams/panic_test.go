@@ -0,0 +1,108 @@
+package streams
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// panicked runs f, expected to raise a *PanicError, and returns it.
+func panicked(t *testing.T, f func()) (perr *PanicError) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		var ok bool
+		perr, ok = r.(*PanicError)
+		assert.True(t, ok, "%v", r)
+	}()
+	f()
+	t.Error("no panic")
+	return nil
+}
+
+func TestPanicInStage(t *testing.T) {
+	perr := panicked(t, func() {
+		Map(New(1, 2, 3), func(i int) int {
+			if i == 2 {
+				panic("boom")
+			}
+			return i
+		}).Collect()
+	})
+	assert.Equal(t, "Map", perr.Stage)
+	assert.Equal(t, "boom", perr.Value)
+	assert.Contains(t, string(perr.Stack), "panic_test.go")
+	assert.Contains(t, perr.Error(), "streams: panic in Map: boom")
+}
+
+func TestPanicInFusedStage(t *testing.T) {
+	boom := errors.New("boom")
+	perr := panicked(t, func() {
+		New(1, 2, 3).Filter(func(i int) bool {
+			return i > 1
+		}).Map(func(i int) int {
+			panic(boom)
+		}).ForEach(func(int) {})
+	})
+	assert.Equal(t, "Filter+Map", perr.Stage)
+	assert.ErrorIs(t, perr, boom)
+}
+
+func TestPanicThroughStages(t *testing.T) {
+	perr := panicked(t, func() {
+		s := New(3, 1, 2).Peek(func(i int) {
+			if i == 2 {
+				panic("boom")
+			}
+		})
+		Sorted(s, ASC).Skip(1).Count()
+	})
+	assert.Equal(t, "Peek", perr.Stage)
+}
+
+func TestPanicInFlatMap(t *testing.T) {
+	perr := panicked(t, func() {
+		FlatMap(New(1, 2), func(i int) *Stream[int] {
+			return New(i, i).Filter(func(int) bool {
+				panic("boom")
+			})
+		}).Collect()
+	})
+	assert.Equal(t, "Filter", perr.Stage)
+}
+
+func TestPanicInJoin(t *testing.T) {
+	perr := panicked(t, func() {
+		SemiJoin(New(1, 2, 3), New(1, 2), func(i int) int {
+			return i
+		}, func(i int) int {
+			panic("boom")
+		}).Collect()
+	})
+	assert.Equal(t, "SemiJoin", perr.Stage)
+}
+
+func TestPanicAfterShortCircuit(t *testing.T) {
+	s := New(1, 2, 3, 4, 5).Filter(func(i int) bool {
+		if i == 4 {
+			panic("boom")
+		}
+		return true
+	})
+	// the panic happens on drained elements only
+	assert.Equal(t, []int{1, 2}, s.Limit(2).Collect())
+}
+
+func TestErrStreamPanic(t *testing.T) {
+	s := Lines(strings.NewReader("a\nb\n")).Map(func(line string) string {
+		panic("boom")
+	})
+	lines, err := s.Collect()
+	assert.Empty(t, lines)
+	var perr *PanicError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, "Map", perr.Stage)
+	}
+}
@@ -0,0 +1,163 @@
+package streams
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallel calls f with every element of s from workers goroutines, all of
+// them when workers is not positive. The first panic of f stops the operation
+// and is raised again as a *PanicError once every worker is done.
+func parallel[T any](s *Stream[T], name string, workers int, f func(worker int, t T)) {
+	workers = workerCount(workers)
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	var once sync.Once
+	var panicked *PanicError
+	call := func(worker int, t T) {
+		defer func() {
+			if r := recover(); r != nil {
+				once.Do(func() {
+					panicked = panicError(name, r)
+				})
+				failed.Store(true)
+			}
+		}()
+		f(worker, t)
+	}
+	work := make(chan T)
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range work {
+				if !failed.Load() {
+					call(worker, t)
+				}
+			}
+		}()
+	}
+	func() {
+		// consume raises the panics of the stages of s, stop the workers
+		// either way
+		defer func() {
+			close(work)
+			wg.Wait()
+		}()
+		consume(s, name, func(t T) bool {
+			work <- t
+			return !failed.Load()
+		})
+	}()
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+func workerCount(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// ParallelForEach calls f with every element of s from workers goroutines, in
+// no particular order. A non positive workers uses GOMAXPROCS goroutines. A
+// panic of f stops the operation and is raised again from ParallelForEach as a
+// *PanicError.
+func ParallelForEach[T any](s *Stream[T], workers int, f func(T)) {
+	parallel(s, "ParallelForEach", workers, func(_ int, t T) {
+		f(t)
+	})
+}
+
+// ParallelCollect folds the elements of s into containers made by supplier,
+// one per worker, with accumulate, then merges the containers with combine on
+// the calling goroutine. Elements are spread over the workers in no particular
+// order, so combine must be associative for the result to be deterministic.
+// Panics are raised again like for ParallelForEach.
+func ParallelCollect[T, C any](s *Stream[T], workers int, supplier func() C, accumulate func(C, T) C, combine func(C, C) C) C {
+	return parallelCollect(s, "ParallelCollect", workers, supplier, accumulate, combine)
+}
+
+func parallelCollect[T, C any](s *Stream[T], name string, workers int, supplier func() C, accumulate func(C, T) C, combine func(C, C) C) C {
+	partials := make([]C, workerCount(workers))
+	for i := range partials {
+		partials[i] = supplier()
+	}
+	parallel(s, name, len(partials), func(worker int, t T) {
+		partials[worker] = accumulate(partials[worker], t)
+	})
+	result := partials[0]
+	for _, partial := range partials[1:] {
+		result = combine(result, partial)
+	}
+	return result
+}
+
+// ParallelReduce is the parallel Reduce: every worker folds its share of the
+// elements with acc starting from a value of its own made by identity, and the
+// partial results are merged with combine. identity must make values neutral
+// for combine.
+func ParallelReduce[T, R any](s *Stream[T], workers int, identity func() R, acc func(R, T) R, combine func(R, R) R) R {
+	return parallelCollect(s, "ParallelReduce", workers, identity, acc, combine)
+}
+
+// ParallelCollectSlice collects the elements of s in no particular order.
+func ParallelCollectSlice[T any](s *Stream[T], workers int) []T {
+	return parallelCollect(s, "ParallelCollectSlice", workers, func() []T {
+		return nil
+	}, func(ts []T, t T) []T {
+		return append(ts, t)
+	}, func(a, b []T) []T {
+		return append(a, b...)
+	})
+}
+
+type partialMap[K comparable, V any] struct {
+	m   map[K]V
+	err error
+}
+
+func (p partialMap[K, V]) put(k K, v V, merge MergeFun[V]) partialMap[K, V] {
+	if p.err != nil {
+		return p
+	}
+	existing, ok := p.m[k]
+	if !ok {
+		p.m[k] = v
+		return p
+	}
+	merged, err := merge(existing, v)
+	if err != nil {
+		p.err = fmt.Errorf("key %v: %w", k, err)
+		return p
+	}
+	p.m[k] = merged
+	return p
+}
+
+// ParallelMCollect is the parallel MCollectMerge. Entries are spread over the
+// workers in no particular order, so merge policies depending on the order,
+// like KeepFirst, keep an arbitrary value.
+func ParallelMCollect[K comparable, V any](s *Stream[MapEntry[K, V]], workers int, merge MergeFun[V]) (map[K]V, error) {
+	result := parallelCollect(s, "ParallelMCollect", workers, func() partialMap[K, V] {
+		return partialMap[K, V]{m: make(map[K]V)}
+	}, func(p partialMap[K, V], t MapEntry[K, V]) partialMap[K, V] {
+		return p.put(t.K, t.V, merge)
+	}, func(a, b partialMap[K, V]) partialMap[K, V] {
+		if b.err != nil && a.err == nil {
+			a.err = b.err
+		}
+		for k, v := range b.m {
+			a = a.put(k, v, merge)
+		}
+		return a
+	})
+	if result.err != nil {
+		return nil, result.err
+	}
+	return result.m, nil
+}
@@ -0,0 +1,132 @@
+package streams
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelForEach(t *testing.T) {
+	var sum atomic.Int64
+	ParallelForEach(New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 4, func(i int) {
+		sum.Add(int64(i))
+	})
+	assert.Equal(t, int64(55), sum.Load())
+}
+
+func TestParallelReduce(t *testing.T) {
+	s := Map(New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), func(i int) int {
+		return i * i
+	})
+	sum := ParallelReduce(s, 3, func() int {
+		return 0
+	}, func(acc, i int) int {
+		return acc + i
+	}, func(a, b int) int {
+		return a + b
+	})
+	assert.Equal(t, 385, sum)
+	assert.Equal(t, 1, ParallelReduce(New[int](), 0, func() int {
+		return 1
+	}, func(acc, i int) int {
+		return acc * i
+	}, func(a, b int) int {
+		return a * b
+	}))
+
+	// every worker folds into a map of its own
+	seen := ParallelReduce(New(1, 2, 3, 4, 5, 6), 3, func() map[int]bool {
+		return make(map[int]bool)
+	}, func(acc map[int]bool, i int) map[int]bool {
+		acc[i] = true
+		return acc
+	}, func(a, b map[int]bool) map[int]bool {
+		for i := range b {
+			a[i] = true
+		}
+		return a
+	})
+	assert.Len(t, seen, 6)
+}
+
+func TestParallelCollect(t *testing.T) {
+	collected := ParallelCollectSlice(New(5, 3, 1, 4, 2), 2)
+	slices.Sort(collected)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collected)
+
+	lengths := ParallelCollect(New("a", "bb", "cc", "ddd"), 3, func() map[int]int {
+		return make(map[int]int)
+	}, func(m map[int]int, s string) map[int]int {
+		m[len(s)]++
+		return m
+	}, func(a, b map[int]int) map[int]int {
+		for k, v := range b {
+			a[k] += v
+		}
+		return a
+	})
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1}, lengths)
+}
+
+func TestParallelMCollect(t *testing.T) {
+	entries := func() *Stream[MapEntry[string, int]] {
+		return New(MapEntry[string, int]{"a", 1}, MapEntry[string, int]{"b", 2}, MapEntry[string, int]{"a", 3})
+	}
+	m, err := ParallelMCollect(entries(), 2, MergeWith(func(a, b int) int {
+		return a + b
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 4, "b": 2}, m)
+
+	m, err = ParallelMCollect(entries(), 2, ErrOnDuplicate)
+	assert.Nil(t, m)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+}
+
+func TestParallelPanic(t *testing.T) {
+	boom := errors.New("boom")
+	var seen atomic.Int32
+	defer func() {
+		r := recover()
+		perr, ok := r.(*PanicError)
+		if assert.True(t, ok, "%v", r) {
+			assert.Equal(t, "ParallelForEach", perr.Stage)
+			assert.ErrorIs(t, perr, boom)
+			assert.Contains(t, string(perr.Stack), "parallel_test.go")
+		}
+		// the stream is drained once a worker panicked
+		assert.Less(t, seen.Load(), int32(1000))
+	}()
+	ParallelForEach(FromSlice(make([]int, 100000)), 2, func(int) {
+		if seen.Add(1) == 3 {
+			panic(boom)
+		}
+	})
+	t.Error("ParallelForEach returned")
+}
+
+func TestParallelUpstreamPanic(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		perr := panicked(t, func() {
+			s := Map(New(1, 2, 3), func(i int) int {
+				if i == 2 {
+					panic("boom")
+				}
+				return i
+			})
+			ParallelForEach(s, 8, func(int) {})
+		})
+		assert.Equal(t, "Map", perr.Stage)
+	}
+	// the workers are stopped, the stages may take a moment to end
+	for wait := 0; wait < 100 && runtime.NumGoroutine() > before; wait++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
@@ -0,0 +1,128 @@
+package streams
+
+import "cmp"
+
+// Op is an operator value: a pipeline fragment that is not bound to a source.
+// It can be stored, composed with Then, Compose2 and friends, and applied any
+// number of times, concurrently as well, every application building a new
+// chain of operations. The functions it is made of are shared by the
+// applications, so they must be safe for concurrent use when the operator is.
+type Op[T, R any] func(s *Stream[T]) *Stream[R]
+
+// IdentityOp passes the elements through, to start building an operator with
+// Then.
+func IdentityOp[T any]() Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s
+	}
+}
+
+// Then returns the operator applying o then next, which keeps the element
+// type. Compose2 chains operators changing it.
+func (o Op[T, R]) Then(next Op[R, R]) Op[T, R] {
+	return Compose2(o, next)
+}
+
+func (o Op[T, R]) Apply(s *Stream[T]) *Stream[R] {
+	if o == nil {
+		panic("streams: Apply on a nil Op")
+	}
+	return o(s)
+}
+
+func (o Op[T, R]) ApplySlice(data []T) *Stream[R] {
+	return o.Apply(FromSlice(data))
+}
+
+func (o Op[T, R]) ApplyChan(ch <-chan T) *Stream[R] {
+	return o.Apply(FromChan(ch))
+}
+
+func MapOp[T, R any](mapper MapFun[T, R]) Op[T, R] {
+	return func(s *Stream[T]) *Stream[R] {
+		return Map(s, mapper)
+	}
+}
+
+func FlatMapOp[T, R any](f func(T) *Stream[R]) Op[T, R] {
+	return func(s *Stream[T]) *Stream[R] {
+		return FlatMap(s, f)
+	}
+}
+
+func FilterOp[T any](filter FilterFun[T]) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.Filter(filter)
+	}
+}
+
+func PeekOp[T any](f func(T)) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.Peek(f)
+	}
+}
+
+func LimitOp[T any](n int) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.Limit(n)
+	}
+}
+
+func SkipOp[T any](n int) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.Skip(n)
+	}
+}
+
+func DropWhileOp[T any](f func(T) bool) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.DropWhile(f)
+	}
+}
+
+func TakeWhileOp[T any](f func(T) bool) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return s.TakeWhile(f)
+	}
+}
+
+func SortedOp[T cmp.Ordered](order SortOrder) Op[T, T] {
+	return func(s *Stream[T]) *Stream[T] {
+		return Sorted(s, order)
+	}
+}
+
+func DistinctOp[T comparable]() Op[T, T] {
+	return Distinct[T]
+}
+
+// Pipe2 applies op1 then op2 to s, Pipe2(s, op1, op2) being op2(op1(s)).
+func Pipe2[A, B, C any](s *Stream[A], op1 Op[A, B], op2 Op[B, C]) *Stream[C] {
+	return op2(op1(s))
+}
+
+func Pipe3[A, B, C, D any](s *Stream[A], op1 Op[A, B], op2 Op[B, C], op3 Op[C, D]) *Stream[D] {
+	return op3(Pipe2(s, op1, op2))
+}
+
+func Pipe4[A, B, C, D, E any](s *Stream[A], op1 Op[A, B], op2 Op[B, C], op3 Op[C, D], op4 Op[D, E]) *Stream[E] {
+	return op4(Pipe3(s, op1, op2, op3))
+}
+
+func Pipe5[A, B, C, D, E, F any](s *Stream[A], op1 Op[A, B], op2 Op[B, C], op3 Op[C, D], op4 Op[D, E], op5 Op[E, F]) *Stream[F] {
+	return op5(Pipe4(s, op1, op2, op3, op4))
+}
+
+// Compose2 returns the operator applying op1 then op2, for pipelines to be
+// stored and applied later.
+func Compose2[A, B, C any](op1 Op[A, B], op2 Op[B, C]) Op[A, C] {
+	return func(s *Stream[A]) *Stream[C] {
+		return Pipe2(s, op1, op2)
+	}
+}
+
+func Compose3[A, B, C, D any](op1 Op[A, B], op2 Op[B, C], op3 Op[C, D]) Op[A, D] {
+	return func(s *Stream[A]) *Stream[D] {
+		return Pipe3(s, op1, op2, op3)
+	}
+}
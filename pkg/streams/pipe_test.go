@@ -0,0 +1,109 @@
+package streams
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipe(t *testing.T) {
+	collected := Pipe3(New(1, 2, 3, 4, 5, 6),
+		FilterOp(func(i int) bool {
+			return i%2 == 0
+		}),
+		MapOp(strconv.Itoa),
+		MapOp(func(s string) string {
+			return "#" + s
+		}),
+	).Collect()
+	assert.Equal(t, []string{"#2", "#4", "#6"}, collected)
+
+	words := Pipe5(New("b a", "c", "a d"),
+		FlatMapOp(func(s string) *Stream[string] {
+			return New(strings.Fields(s)...)
+		}),
+		DistinctOp[string](),
+		SortedOp[string](DESC),
+		SkipOp[string](1),
+		LimitOp[string](2),
+	).Collect()
+	assert.Equal(t, []string{"c", "b"}, words)
+}
+
+func TestCompose(t *testing.T) {
+	lengths := Compose3(
+		TakeWhileOp(func(i int) bool {
+			return i < 100
+		}),
+		MapOp(strconv.Itoa),
+		MapOp(func(s string) int {
+			return len(s)
+		}),
+	)
+	// the same operator applies to several sources
+	assert.Equal(t, []int{1, 2}, lengths(New(7, 42, 100, 3)).Collect())
+
+	var peeked []int
+	assert.Equal(t, []int{2, 2}, Pipe3(New(1, 11, 12),
+		lengths,
+		PeekOp(func(i int) {
+			peeked = append(peeked, i)
+		}),
+		DropWhileOp(func(i int) bool {
+			return i < 2
+		}),
+	).Collect())
+	assert.Equal(t, []int{1, 2, 2}, peeked)
+}
+
+func TestOpApply(t *testing.T) {
+	evens := IdentityOp[int]().
+		Then(FilterOp(func(i int) bool {
+			return i%2 == 0
+		})).
+		Then(SortedOp[int](ASC))
+	labels := Compose3(evens, MapOp(strconv.Itoa), MapOp(func(s string) string {
+		return "<" + s + ">"
+	})).Then(LimitOp[string](2))
+
+	assert.Equal(t, []string{"<2>", "<4>"}, labels.ApplySlice([]int{5, 4, 3, 2, 1}).Collect())
+	assert.Equal(t, []string{"<0>"}, labels.Apply(New(0, 1)).Collect())
+
+	ch := make(chan int, 3)
+	ch <- 8
+	ch <- 6
+	ch <- 7
+	close(ch)
+	assert.Equal(t, []int{6, 8}, evens.ApplyChan(ch).Collect())
+
+	assert.Equal(t, []string{"<10>"}, Pipe2(New(10, 20), SkipOp[int](0), labels).Limit(1).Collect())
+}
+
+func TestOpConcurrent(t *testing.T) {
+	squares := MapOp(func(i int) int {
+		return i * i
+	}).Then(SkipOp[int](1))
+	var wg sync.WaitGroup
+	sums := make([]int, 20)
+	for i := range sums {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sums[i] = Sum(squares.ApplySlice([]int{i, 1, 2}))
+		}()
+	}
+	wg.Wait()
+	for _, sum := range sums {
+		assert.Equal(t, 5, sum)
+	}
+}
+
+func TestNilOp(t *testing.T) {
+	var o Op[int, string]
+	assert.PanicsWithValue(t, "streams: Apply on a nil Op", func() {
+		o.Apply(New(1))
+	})
+}
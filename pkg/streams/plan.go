@@ -0,0 +1,174 @@
+package streams
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// planNode is the logical plan node of an operator, recorded when the
+// operator is chained so that a pipeline can be explained before it runs. IDs
+// are given once the plan is final, see pipeline.number.
+type planNode struct {
+	id     int
+	name   string
+	args   string
+	inputs []*planNode
+	// panicked is the panic that ended the stage, set before its output is
+	// closed, see recoverStage.
+	panicked *PanicError
+	// stop is closed once the stage reading the output of this one stopped
+	// reading it, see stopStream.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type operatorTraits struct {
+	// stateful operators keep elements across calls, so they hold memory
+	// and may have to read their whole input before emitting anything.
+	stateful bool
+	// shortCircuit operators can stop reading their input early.
+	shortCircuit bool
+}
+
+var traits = map[string]operatorTraits{
+	"Limit":            {shortCircuit: true},
+	"TakeWhile":        {shortCircuit: true},
+	"TakeWhileIndexed": {shortCircuit: true},
+	"Reverse":          {stateful: true},
+	"Sorted":           {stateful: true},
+	"MSorted":          {stateful: true},
+	"Distinct":         {stateful: true},
+	"Debounce":         {stateful: true},
+	"TumblingWindow":   {stateful: true},
+	"SlidingWindow":    {stateful: true},
+	"SessionWindow":    {stateful: true},
+	"AggregateByKey":   {stateful: true},
+	"ReduceByKey":      {stateful: true},
+	"Join":             {stateful: true},
+	"LeftJoin":         {stateful: true},
+	"FullOuterJoin":    {stateful: true},
+	"SemiJoin":         {stateful: true},
+	"AntiJoin":         {stateful: true},
+	"MergeJoin":        {stateful: true},
+}
+
+func newPlanNode(name string, inputs ...*planNode) *planNode {
+	return &planNode{name: name, inputs: inputs, stop: make(chan struct{})}
+}
+
+func (n *planNode) traits() operatorTraits {
+	return traits[n.name]
+}
+
+func (n *planNode) label() string {
+	label := n.name
+	if n.args != "" {
+		label += "(" + n.args + ")"
+	}
+	var flags []string
+	if n.traits().stateful {
+		flags = append(flags, "stateful")
+	}
+	if n.traits().shortCircuit {
+		flags = append(flags, "short-circuit")
+	}
+	if len(flags) > 0 {
+		label += " [" + strings.Join(flags, ", ") + "]"
+	}
+	return label
+}
+
+// withArgs records the arguments of the operator that produced s, for Explain.
+func (s *Stream[T]) withArgs(args ...any) *Stream[T] {
+	if s.node != nil {
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			parts[i] = fmt.Sprint(arg)
+		}
+		s.node.args = strings.Join(parts, ", ")
+	}
+	return s
+}
+
+// withInput records in as a second input of the operator that produced s.
+func withInput[T, U any](s *Stream[T], in *Stream[U]) *Stream[T] {
+	if s.node != nil {
+		s.node.inputs = append(s.node.inputs, in.node)
+	}
+	return s
+}
+
+// Explain describes the operators s is made of, as a tree rooted at the last
+// one and growing towards the sources. Every line holds the stage ID, which
+// StageStats report as well, the operator with a summary of its arguments and
+// whether it is stateful or short circuiting:
+//
+//	#2 Limit(3) [short-circuit]
+//	└─ #1 Filter
+//	   └─ #0 New(5 elements)
+//
+// The plan is the optimized one the stream runs with, where fused operators
+// show as a single stage such as Filter+Map. Terminal operations are not part
+// of the plan, and neither are the streams returned by the function given to
+// FlatMap.
+func (s *Stream[T]) Explain() string {
+	var b strings.Builder
+	var walk func(n *planNode, prefix, childPrefix string)
+	walk = func(n *planNode, prefix, childPrefix string) {
+		fmt.Fprintf(&b, "%s#%d %s\n", prefix, n.id, n.label())
+		inputs := explained(n.inputs)
+		for i, in := range inputs {
+			if i == len(inputs)-1 {
+				walk(in, childPrefix+"└─ ", childPrefix+"   ")
+			} else {
+				walk(in, childPrefix+"├─ ", childPrefix+"│  ")
+			}
+		}
+	}
+	if s.node != nil {
+		s.pipeline().number(s.node)
+		walk(s.node, "", "")
+	}
+	return b.String()
+}
+
+// ExplainDOT is like Explain but describes the plan in the DOT language of
+// Graphviz, with edges going the way elements flow.
+func (s *Stream[T]) ExplainDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph stream {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	seen := make(map[*planNode]bool)
+	var walk func(n *planNode)
+	walk = func(n *planNode) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		for _, in := range explained(n.inputs) {
+			walk(in)
+		}
+		fmt.Fprintf(&b, "\tn%d [label=%q];\n", n.id, n.label())
+		for _, in := range explained(n.inputs) {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", in.id, n.id)
+		}
+	}
+	if s.node != nil {
+		s.pipeline().number(s.node)
+		walk(s.node)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// explained drops the inputs that are not part of any plan, like the streams
+// built from the result of a terminal operation.
+func explained(inputs []*planNode) []*planNode {
+	var nodes []*planNode
+	for _, in := range inputs {
+		if in != nil {
+			nodes = append(nodes, in)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,64 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	s := New(5, 3, 1, 4, 2).Filter(func(i int) bool {
+		return i > 1
+	})
+	s = Sorted(s, DESC).Limit(2)
+	assert.Equal(t, "#3 Limit(2) [short-circuit]\n"+
+		"└─ #2 Sorted(DESC) [stateful]\n"+
+		"   └─ #1 Filter\n"+
+		"      └─ #0 New(5 elements)\n", s.Explain())
+
+	// explaining does not run the stream
+	assert.Equal(t, []int{5, 4}, s.Collect())
+}
+
+func TestExplainJoin(t *testing.T) {
+	left := New(1, 2, 3).Map(func(i int) int {
+		return i * 2
+	})
+	right := New("a", "bb").Skip(1)
+	joined := Join(left, right, func(i int) int {
+		return i
+	}, func(s string) int {
+		return len(s)
+	})
+	// Join dereferences the pairs of the underlying hash join in a Map
+	assert.Equal(t, "#5 Map\n"+
+		"└─ #4 Join [stateful]\n"+
+		"   ├─ #1 Map\n"+
+		"   │  └─ #0 New(3 elements)\n"+
+		"   └─ #3 Skip(1)\n"+
+		"      └─ #2 New(2 elements)\n", joined.Explain())
+
+	recorder := NewMetricsRecorder()
+	joined.WithObserver(recorder)
+	assert.Len(t, joined.Collect(), 1)
+	ids := make(map[int]string)
+	for _, stats := range recorder.Stats() {
+		ids[stats.ID] = stats.Name
+	}
+	assert.Equal(t, map[int]string{0: "New", 1: "Map", 2: "New", 3: "Skip", 4: "Join", 5: "Map", 6: "Collect"}, ids)
+}
+
+func TestExplainDOT(t *testing.T) {
+	s := ToNumberStream(Map(New("a", "bb"), func(s string) int {
+		return len(s)
+	})).Skip(1)
+	assert.Equal(t, "digraph stream {\n"+
+		"\trankdir=LR;\n"+
+		"\tnode [shape=box];\n"+
+		"\tn0 [label=\"New(2 elements)\"];\n"+
+		"\tn1 [label=\"Map\"];\n"+
+		"\tn0 -> n1;\n"+
+		"\tn2 [label=\"Skip(1)\"];\n"+
+		"\tn1 -> n2;\n"+
+		"}\n", s.ExplainDOT())
+}
@@ -0,0 +1,147 @@
+package streams
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// TryMapFun maps an element like MapFun but can fail.
+type TryMapFun[T, R any] func(T) (R, error)
+
+// RetryPolicy says how often and how long apart a failing call is retried.
+// The zero policy calls once without retrying.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made for an element, the first one
+	// included.
+	MaxAttempts int
+	// InitialBackoff is the pause before the first retry.
+	InitialBackoff time.Duration
+	// Multiplier grows the pause after every retry, 2 when not positive.
+	Multiplier float64
+	// MaxBackoff caps the pause before jitter is applied, when positive.
+	MaxBackoff time.Duration
+	// Jitter spreads the pauses of concurrent callers, picking each one at
+	// random within Jitter times the pause of either side of it. It is a
+	// fraction between 0 and 1.
+	Jitter float64
+	// Retryable tells the errors worth retrying, all of them when nil.
+	Retryable func(error) bool
+	// Clock sleeps between attempts, SystemClock when nil.
+	Clock Clock
+}
+
+func (p RetryPolicy) String() string {
+	return fmt.Sprintf("%d attempts", max(p.MaxAttempts, 1))
+}
+
+// backoff returns the pause before the retry following the given attempt,
+// counted from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < float64(p.MaxBackoff)); i++ {
+		d *= multiplier
+	}
+	if p.MaxBackoff > 0 {
+		d = min(d, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// Retry returns f calling it again, as told by policy, as long as it fails.
+// The returned error wraps the last error of f.
+func Retry[T, R any](f TryMapFun[T, R], policy RetryPolicy) TryMapFun[T, R] {
+	clock := policy.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return func(t T) (R, error) {
+		for attempt := 1; ; attempt++ {
+			r, err := f(t)
+			if err == nil {
+				return r, nil
+			}
+			if policy.Retryable != nil && !policy.Retryable(err) {
+				return r, err
+			}
+			if attempt >= policy.MaxAttempts {
+				return r, fmt.Errorf("streams: giving up after %d attempts: %w", attempt, err)
+			}
+			clock.Sleep(policy.backoff(attempt))
+		}
+	}
+}
+
+// MapRetry maps every element with f, retried as told by policy. The stream
+// ends with the error of the first element f keeps failing for.
+func MapRetry[T, R any](s *Stream[T], f TryMapFun[T, R], policy RetryPolicy) *ErrStream[R] {
+	errs := &errHolder{}
+	retry := Retry(f, policy)
+	return toErrStream(derive(s, "MapRetry", func(st *stage[T, R]) {
+		st.each(func(t T) bool {
+			r, err := retry(t)
+			if err != nil {
+				errs.set(err)
+				return false
+			}
+			st.send(r)
+			return true
+		})
+	}).withArgs(policy), errs)
+}
+
+// OnErrorContinue maps every element with f, skipping the elements it fails
+// for after passing them to onError, when set.
+func OnErrorContinue[T, R any](s *Stream[T], f TryMapFun[T, R], onError func(T, error)) *Stream[R] {
+	return fuse(s, "OnErrorContinue", func(t T) (R, bool) {
+		r, err := f(t)
+		if err != nil {
+			if onError != nil {
+				onError(t, err)
+			}
+			return r, false
+		}
+		return r, true
+	})
+}
+
+// OnErrorResume returns the elements of s followed, when s ends with an error,
+// by the elements of the stream fallback returns for that error. The error is
+// not reported any further.
+func OnErrorResume[T any](s *ErrStream[T], fallback func(error) *Stream[T]) *Stream[T] {
+	return derive(s.ToStream(), "OnErrorResume", func(st *stage[T, T]) {
+		read := st.each(func(t T) bool {
+			st.send(t)
+			return true
+		})
+		// the output is no longer read
+		if !read {
+			return
+		}
+		err := s.Err()
+		if err == nil {
+			return
+		}
+		if resumed := fallback(err); resumed != nil {
+			resumed.Run()
+			for t := range resumed.data {
+				if !st.send(t) {
+					stopStream(resumed.node, resumed.data)
+					return
+				}
+			}
+			rethrow(resumed.node)
+		}
+	})
+}
+
+func (s *ErrStream[T]) OnErrorResume(fallback func(error) *Stream[T]) *Stream[T] {
+	return OnErrorResume(s, fallback)
+}
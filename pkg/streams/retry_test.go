@@ -0,0 +1,153 @@
+package streams
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFlaky = errors.New("flaky")
+
+// flaky fails the first failures calls for every element.
+func flaky(failures int) TryMapFun[int, string] {
+	calls := make(map[int]int)
+	return func(i int) (string, error) {
+		calls[i]++
+		if calls[i] <= failures {
+			return "", errFlaky
+		}
+		return strconv.Itoa(i), nil
+	}
+}
+
+func TestMapRetry(t *testing.T) {
+	clock := &testClock{}
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, Clock: clock}
+	collected, err := MapRetry(New(1, 2), flaky(2), policy).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, collected)
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond,
+		100 * time.Millisecond, 200 * time.Millisecond,
+	}, clock.slept)
+}
+
+func TestMapRetryGivesUp(t *testing.T) {
+	clock := &testClock{}
+	policy := RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Second, Clock: clock}
+	collected, err := MapRetry(New(1, 2), flaky(2), policy).Collect()
+	assert.Empty(t, collected)
+	assert.ErrorIs(t, err, errFlaky)
+	assert.EqualError(t, err, "streams: giving up after 2 attempts: flaky")
+	assert.Equal(t, []time.Duration{time.Second}, clock.slept)
+}
+
+func TestMapRetryNotRetryable(t *testing.T) {
+	clock := &testClock{}
+	policy := RetryPolicy{MaxAttempts: 5, Clock: clock, Retryable: func(err error) bool {
+		return !errors.Is(err, errFlaky)
+	}}
+	_, err := MapRetry(New(1), flaky(1), policy).Collect()
+	assert.Equal(t, errFlaky, err)
+	assert.Empty(t, clock.slept)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, Multiplier: 3, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 3*time.Second, policy.backoff(2))
+	assert.Equal(t, 5*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(100))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.backoff(1)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+		assert.LessOrEqual(t, d, 1500*time.Millisecond)
+	}
+}
+
+func TestOnErrorContinue(t *testing.T) {
+	var failed []int
+	s := OnErrorContinue(New(1, 2, 3, 4), func(i int) (int, error) {
+		if i%2 == 0 {
+			return 0, errFlaky
+		}
+		return i * 10, nil
+	}, func(i int, err error) {
+		assert.Equal(t, errFlaky, err)
+		failed = append(failed, i)
+	})
+	assert.Equal(t, []int{10, 30}, s.Collect())
+	assert.Equal(t, []int{2, 4}, failed)
+}
+
+func TestOnErrorContinueWithRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, Clock: &testClock{}}
+	s := OnErrorContinue(New(1, 2), Retry(flaky(1), policy), nil)
+	assert.Equal(t, []string{"1", "2"}, s.Collect())
+}
+
+func TestOnErrorResume(t *testing.T) {
+	policy := RetryPolicy{Clock: &testClock{}}
+	failing := MapRetry(New(1, 2, 3), func(i int) (int, error) {
+		if i == 3 {
+			return 0, errFlaky
+		}
+		return i, nil
+	}, policy)
+	var resumedFrom error
+	s := failing.OnErrorResume(func(err error) *Stream[int] {
+		resumedFrom = err
+		return New(-1)
+	})
+	assert.Equal(t, []int{1, 2, -1}, s.Collect())
+	assert.ErrorIs(t, resumedFrom, errFlaky)
+}
+
+func TestOnErrorResumeShortCircuit(t *testing.T) {
+	failing := MapRetry(New(1, 2), func(i int) (int, error) {
+		if i == 2 {
+			return 0, errFlaky
+		}
+		return i, nil
+	}, RetryPolicy{Clock: &testClock{}})
+	var read atomic.Int64
+	s := failing.OnErrorResume(func(error) *Stream[int] {
+		return counted(1000, &read)
+	})
+	assert.Equal(t, []int{1, 0}, s.Limit(2).Collect())
+	assertStopped(t, &read, 1000)
+
+	// no fallback once the output is no longer read
+	failing = MapRetry(New(1, 2, 3), func(i int) (int, error) {
+		if i == 3 {
+			return 0, errFlaky
+		}
+		return i, nil
+	}, RetryPolicy{Clock: &testClock{}})
+	s = failing.OnErrorResume(func(error) *Stream[int] {
+		t.Error("resumed")
+		return nil
+	})
+	assert.Equal(t, []int{1}, s.Limit(1).Collect())
+}
+
+func TestOnErrorResumeWithoutError(t *testing.T) {
+	s := OnErrorResume(Lines(strings.NewReader("a\nb\n")), func(error) *Stream[string] {
+		t.Error("resumed")
+		return nil
+	})
+	assert.Equal(t, []string{"a", "b"}, s.Collect())
+}
+
+func TestMapRetryExplain(t *testing.T) {
+	s := MapRetry(New(1), flaky(0), RetryPolicy{MaxAttempts: 3})
+	assert.Equal(t, "#1 MapRetry(3 attempts)\n"+
+		"└─ #0 New(1 elements)\n", s.Explain())
+}
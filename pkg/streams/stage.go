@@ -0,0 +1,335 @@
+package streams
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// pipeline is shared by all the stages of a chain, so that options set on any
+// stream of the chain apply to every stage once it runs.
+type pipeline struct {
+	mu       sync.Mutex
+	merged   *pipeline
+	observer Observer
+	tracer   Tracer
+}
+
+func (p *pipeline) root() *pipeline {
+	for p.merged != nil {
+		p = p.merged
+	}
+	return p
+}
+
+// merge makes other share the options of p, for operators reading from several
+// streams.
+func (p *pipeline) merge(other *pipeline) {
+	p, other = p.root(), other.root()
+	if p == other {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	if p.observer == nil {
+		p.observer = other.observer
+	}
+	if p.tracer == nil {
+		p.tracer = other.tracer
+	}
+	other.merged = p
+}
+
+// number gives the stages of the plan ending with n their IDs, sources first,
+// and returns the next free ID. The plan is only final once a terminal
+// operation is called, since chaining can rewrite it.
+func (p *pipeline) number(n *planNode) int {
+	p = p.root()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	next := 0
+	seen := make(map[*planNode]bool)
+	var walk func(n *planNode)
+	walk = func(n *planNode) {
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		for _, in := range n.inputs {
+			walk(in)
+		}
+		n.id = next
+		next++
+	}
+	walk(n)
+	return next
+}
+
+// hooks returns the ID of the stage n along with the options of the pipeline.
+func (p *pipeline) hooks(n *planNode) (int, Observer, Tracer) {
+	p = p.root()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return n.id, p.observer, p.tracer
+}
+
+func (p *pipeline) setObserver(obs Observer) {
+	p = p.root()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.observer = obs
+}
+
+func (p *pipeline) setTracer(t Tracer) {
+	p = p.root()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tracer = t
+}
+
+func (s *Stream[T]) pipeline() *pipeline {
+	if s.pipe == nil {
+		s.pipe = &pipeline{}
+	}
+	return s.pipe.root()
+}
+
+// meter measures a stage when the pipeline is observed or traced.
+type meter struct {
+	measure  bool
+	observer Observer
+	span     StageSpan
+	stats    StageStats
+	start    time.Time
+}
+
+func newMeter(p *pipeline, n *planNode) *meter {
+	id, observer, tracer := p.hooks(n)
+	m := &meter{
+		measure:  observer != nil || tracer != nil,
+		observer: observer,
+	}
+	if m.measure {
+		m.stats = StageStats{ID: id, Name: n.name, Source: len(explained(n.inputs)) == 0}
+		m.start = time.Now()
+	}
+	if tracer != nil {
+		m.span = tracer.StartStage(id, n.name)
+	}
+	return m
+}
+
+// received counts an element read by a stage that reads its input without
+// each.
+func (m *meter) received() {
+	if m.measure {
+		m.stats.In++
+	}
+}
+
+func (m *meter) done() {
+	if !m.measure {
+		return
+	}
+	m.stats.Elapsed = time.Since(m.start)
+	if m.span != nil {
+		m.span.End(m.stats)
+	}
+	if m.observer != nil {
+		m.observer.StageDone(m.stats)
+	}
+}
+
+// stage runs one operator of a pipeline, reading from in, produced by the
+// stage from, and writing to out until the stage reading out stops.
+type stage[T, R any] struct {
+	*meter
+	from    *planNode
+	in      <-chan T
+	out     chan<- R
+	stop    <-chan struct{}
+	stopped bool
+}
+
+func newStage[T, R any](p *pipeline, n *planNode, from *planNode, in <-chan T, out chan<- R) *stage[T, R] {
+	return &stage[T, R]{meter: newMeter(p, n), from: from, in: in, out: out, stop: n.stop}
+}
+
+// each calls f with every input element until f returns false or the output
+// is no longer read, in which case the upstream stage is told to stop. It
+// reports whether the whole input was consumed, and raises again the panic
+// that ended it if any.
+func (st *stage[T, R]) each(f func(T) bool) bool {
+	if !st.measure {
+		for t := range st.in {
+			if !f(t) || st.stopped {
+				st.stopInput()
+				return false
+			}
+		}
+		rethrow(st.from)
+		return true
+	}
+	for {
+		waitFrom := time.Now()
+		t, ok := <-st.in
+		busyFrom := time.Now()
+		st.stats.RecvWait += busyFrom.Sub(waitFrom)
+		if !ok {
+			rethrow(st.from)
+			return true
+		}
+		st.stats.In++
+		sendWait := st.stats.SendWait
+		more := f(t)
+		st.stats.Busy += time.Since(busyFrom) - (st.stats.SendWait - sendWait)
+		if !more || st.stopped {
+			st.stopInput()
+			return false
+		}
+	}
+}
+
+// send passes r to the stage reading the output and reports whether that
+// stage still reads it. Once it stopped, r is dropped and so are the elements
+// sent afterwards, so a stage producing many elements on its own should stop
+// as soon as send returns false.
+func (st *stage[T, R]) send(r R) bool {
+	if st.stopped {
+		return false
+	}
+	select {
+	case <-st.stop:
+		st.stopped = true
+		return false
+	default:
+	}
+	var waitFrom time.Time
+	if st.measure {
+		waitFrom = time.Now()
+	}
+	select {
+	case st.out <- r:
+	case <-st.stop:
+		st.stopped = true
+		return false
+	}
+	if st.measure {
+		st.stats.SendWait += time.Since(waitFrom)
+		st.stats.Out++
+	}
+	return true
+}
+
+// stopInput tells the upstream stage to stop, once the output of st is no
+// longer read or st does not need the rest of its input.
+func (st *stage[T, R]) stopInput() {
+	stopStream(st.from, st.in)
+}
+
+// stopStream tells the stage n producing in to stop, so that it never blocks
+// on a send. in is drained instead when it is not produced by a stage.
+func stopStream[T any](n *planNode, in <-chan T) {
+	if n == nil {
+		go drain(in)
+		return
+	}
+	n.stopOnce.Do(func() {
+		close(n.stop)
+	})
+}
+
+// A panic of a stage, most likely of a user callback, cannot be recovered by
+// the caller of the terminal operation since the stage runs in a goroutine of
+// its own. Instead the stage records it as a *PanicError on its plan node and
+// closes its output, and the stage reading that output raises it again once it
+// reaches the end of its input, so that the panic travels downstream up to the
+// terminal operation. A stage that stopped reading its input early does not
+// raise the panics of the upstream stages, which only ran on drained elements.
+
+// recoverStage is deferred by the stage n reading from in, produced by the
+// stage from: it records the panic of the stage, if any, and stops the
+// upstream stages.
+func recoverStage[T any](n, from *planNode, in <-chan T) {
+	if r := recover(); r != nil {
+		n.panicked = panicError(n.name, r)
+		if in != nil {
+			stopStream(from, in)
+		}
+	}
+}
+
+// panicError wraps the panic value r of the stage name, unless it already is a
+// *PanicError raised again from an upstream stage.
+func panicError(name string, r any) *PanicError {
+	if err, ok := r.(*PanicError); ok {
+		return err
+	}
+	return &PanicError{Stage: name, Value: r, Stack: debug.Stack()}
+}
+
+// rethrow raises again the panic that ended the output of any of the stages
+// nodes. It must only be called once that output was read to the end.
+func rethrow(nodes ...*planNode) {
+	for _, n := range nodes {
+		if n != nil && n.panicked != nil {
+			panic(n.panicked)
+		}
+	}
+}
+
+// derive returns the stream produced by the operator name reading from s. body
+// runs in the stage goroutine once the stream runs, the output is closed when
+// body returns.
+func derive[T, R any](s *Stream[T], name string, body func(st *stage[T, R])) *Stream[R] {
+	ch := make(chan R)
+	p := s.pipeline()
+	n := newPlanNode(name, s.node)
+	return &Stream[R]{
+		data: ch,
+		pipe: p,
+		node: n,
+		run: func() {
+			s.Run()
+			defer close(ch)
+			st := newStage(p, n, s.node, s.data, ch)
+			defer st.done()
+			defer recoverStage(n, s.node, s.data)
+			body(st)
+		},
+	}
+}
+
+// source returns a stream whose elements are produced by body.
+func source[T any](name string, body func(st *stage[T, T])) *Stream[T] {
+	ch := make(chan T)
+	p := &pipeline{}
+	n := newPlanNode(name)
+	return &Stream[T]{
+		data: ch,
+		pipe: p,
+		node: n,
+		run: func() {
+			defer close(ch)
+			st := newStage[T, T](p, n, nil, nil, ch)
+			defer st.done()
+			defer recoverStage[T](n, nil, nil)
+			body(st)
+		},
+	}
+}
+
+// consume runs the terminal operation name, calling f with every element of s
+// until it returns false. A panic of a stage of s is raised again as a
+// *PanicError on the calling goroutine.
+func consume[T any](s *Stream[T], name string, f func(T) bool) {
+	p := s.pipeline()
+	n := newPlanNode(name, s.node)
+	p.number(n)
+	s.Run()
+	st := newStage[T, T](p, n, s.node, s.data, nil)
+	defer st.done()
+	st.each(f)
+}
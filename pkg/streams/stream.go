@@ -2,6 +2,7 @@ package streams
 
 import (
 	"cmp"
+	"fmt"
 	"sort"
 	"sync/atomic"
 )
@@ -14,6 +15,9 @@ type Stream[T any] struct {
 	data chan T
 	run  func()
 	ran  atomic.Bool
+	pipe *pipeline
+	node *planNode
+	opt  *rewrites[T]
 }
 
 func (s *Stream[T]) Run() {
@@ -23,81 +27,62 @@ func (s *Stream[T]) Run() {
 }
 
 func New[T any](data ...T) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			func(tasks []T) {
-				defer close(ch)
-				for _, task := range tasks {
-					ch <- task
-				}
-			}(data)
-		},
-	}
+	return source("New", func(st *stage[T, T]) {
+		for _, t := range data {
+			st.send(t)
+		}
+	}).withArgs(fmt.Sprintf("%d elements", len(data)))
 }
 
 func FromSlice[T any](arr []T) *Stream[T] {
 	return New(arr...)
 }
 
+// FromChan streams the elements received from ch until it is closed.
+func FromChan[T any](ch <-chan T) *Stream[T] {
+	return source("FromChan", func(st *stage[T, T]) {
+		for t := range ch {
+			st.send(t)
+		}
+	})
+}
+
 func (s *Stream[T]) Filter(filter FilterFun[T]) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				if filter(t) {
-					ch <- t
-				}
-			}
-		},
-	}
+	return fuse(s, "Filter", func(t T) (T, bool) {
+		return t, filter(t)
+	})
 }
 
 func (s *Stream[T]) Limit(i int) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			func() {
-				defer close(ch)
-				for t := range s.data {
-					if i > 0 {
-						ch <- t
-						i--
-					} else {
-						break
-					}
-				}
-			}()
-
-			for range s.data {
-			}
-
-		},
+	if limit := s.rewrite().limit; limit != nil {
+		return limit(i)
 	}
+	return derive(s, "Limit", func(st *stage[T, T]) {
+		st.each(func(t T) bool {
+			if i <= 0 {
+				return false
+			}
+			st.send(t)
+			i--
+			return i > 0
+		})
+	}).withArgs(i)
 }
 
 func (s *Stream[T]) ForEach(f func(i T)) {
-	s.Run()
-	for t := range s.data {
+	consume(s, "ForEach", func(t T) bool {
 		f(t)
-	}
+		return true
+	})
 }
 
 func (s *Stream[T]) AllMatch(f func(T) bool) bool {
-	s.Run()
-	for t := range s.data {
-		if !f(t) {
-			go drain(s.data)
-			return false
-		}
-	}
-	return true
+	allMatch := true
+	consume(s, "AllMatch", func(t T) bool {
+		allMatch = f(t)
+		return allMatch
+	})
+	return allMatch
 }
 
 func (s *Stream[T]) NotAllMatch(f func(T) bool) bool {
@@ -105,14 +90,12 @@ func (s *Stream[T]) NotAllMatch(f func(T) bool) bool {
 }
 
 func (s *Stream[T]) AnyMatch(f func(T) bool) bool {
-	s.Run()
-	for t := range s.data {
-		if f(t) {
-			go drain(s.data)
-			return true
-		}
-	}
-	return false
+	anyMatch := false
+	consume(s, "AnyMatch", func(t T) bool {
+		anyMatch = f(t)
+		return !anyMatch
+	})
+	return anyMatch
 }
 
 func (s *Stream[T]) NoneMatch(f func(T) bool) bool {
@@ -120,58 +103,36 @@ func (s *Stream[T]) NoneMatch(f func(T) bool) bool {
 }
 
 func (s *Stream[T]) DropWhile(f func(T) bool) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			dropping := true
-			for t := range s.data {
-				if dropping && f(t) {
-					continue
-				}
-				dropping = false
-				ch <- t
+	return derive(s, "DropWhile", func(st *stage[T, T]) {
+		dropping := true
+		st.each(func(t T) bool {
+			if dropping && f(t) {
+				return true
 			}
-		},
-	}
+			dropping = false
+			st.send(t)
+			return true
+		})
+	})
 }
 
 func (s *Stream[T]) TakeWhile(f func(T) bool) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			func() {
-				defer close(ch)
-				for t := range s.data {
-					if f(t) {
-						ch <- t
-					} else {
-						break
-					}
-				}
-			}()
-			go drain(s.data)
-		},
-	}
+	return derive(s, "TakeWhile", func(st *stage[T, T]) {
+		st.each(func(t T) bool {
+			if !f(t) {
+				return false
+			}
+			st.send(t)
+			return true
+		})
+	})
 }
 
 func (s *Stream[T]) Peek(f func(T)) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				f(t)
-				ch <- t
-			}
-		},
-	}
+	return fuse(s, "Peek", func(t T) (T, bool) {
+		f(t)
+		return t, true
+	})
 }
 
 type OrStream[T any] struct {
@@ -179,45 +140,32 @@ type OrStream[T any] struct {
 }
 
 func (s *OrStream[T]) Or(or T) T {
-	s.Run()
-	for t := range s.data {
-		go drain(s.data)
-		return t
-	}
+	consume(&s.Stream, "Or", func(t T) bool {
+		or = t
+		return false
+	})
 	return or
 }
 
 func (s *Stream[T]) FindFirst() *T {
-	s.Run()
-	for t := range s.data {
-		go drain(s.data)
-		return &t
-	}
-	return nil
+	return FindFirst(s)
 }
 
+// FindFirstOr returns a stream of the first element, whose Or method provides
+// a default when there is none.
+//
+// Deprecated: FindFirstOptional().OrElse does the same without a stream.
 func (s *Stream[T]) FindFirstOr() *OrStream[T] {
-	s.Run()
-	for t := range s.data {
-		go drain(s.data)
-		ch := make(chan T)
-		return &OrStream[T]{
-			Stream[T]{
-				data: ch,
-				run: func() {
-					defer close(ch)
-					ch <- t
-				},
-				ran: atomic.Bool{},
-			},
-		}
-	}
+	first := FindFirst(s)
 	ch := make(chan T)
 	return &OrStream[T]{
 		Stream[T]{
 			data: ch,
 			run: func() {
 				defer close(ch)
+				if first != nil {
+					ch <- *first
+				}
 			},
 			ran: atomic.Bool{},
 		},
@@ -225,174 +173,74 @@ func (s *Stream[T]) FindFirstOr() *OrStream[T] {
 }
 
 func (s *Stream[T]) Skip(n int) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				if n > 0 {
-					n--
-					continue
-				}
-				ch <- t
+	if skip := s.rewrite().skip; skip != nil {
+		return skip(n)
+	}
+	skipped := derive(s, "Skip", func(st *stage[T, T]) {
+		left := n
+		st.each(func(t T) bool {
+			if left > 0 {
+				left--
+				return true
 			}
-		},
-	}
-}
-
-type ElseStream[T any] struct {
-	Stream[T]
-}
-
-func (s *ElseStream[T]) Else(action func(t T)) {
-	s.Run()
-	for t := range s.data {
-		action(t)
-	}
-}
-
-func (s *Stream[T]) IfAllMatch(f func(T) bool, action func(t T)) *ElseStream[T] {
-	s.Run()
-	allMatch := true
-	var data []T
-	for t := range s.data {
-		if allMatch && !f(t) {
-			allMatch = false
-		}
-		data = append(data, t)
-	}
-	if allMatch {
-		for _, t := range data {
-			action(t)
-		}
-	}
-	ch := make(chan T)
-	return &ElseStream[T]{
-		Stream: Stream[T]{
-			data: ch,
-			run: func() {
-				defer close(ch)
-				for _, t := range data {
-					ch <- t
-				}
-			},
-			ran: atomic.Bool{},
-		},
-	}
+			st.send(t)
+			return true
+		})
+	}).withArgs(n)
+	skipped.opt = &rewrites[T]{skip: func(m int) *Stream[T] {
+		// a negative count skips nothing
+		return s.Skip(max(n, 0) + max(m, 0))
+	}}
+	return skipped
 }
 
 func (s *Stream[T]) Collect() []T {
-	return Reduce(s, []T{}, func(ans []T, i T) []T {
-		return append(ans, i)
-	})
+	return Collect(s)
 }
 
 type UnaryMapFun[T any] func(T) T
 
 func (s *Stream[T]) Map(mapper UnaryMapFun[T]) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				ch <- mapper(t)
-			}
-		},
-	}
+	return Map(s, MapFun[T, T](mapper))
 }
 
 func (s *Stream[T]) Reduce(result T, f func(ans T, i T) T) T {
-	s.Run()
-	for t := range s.data {
-		result = f(result, t)
-	}
-	return result
+	return Reduce(s, result, f)
 }
 
 func (s *Stream[T]) Count() (cnt int64) {
-	s.Run()
-	for range s.data {
-		cnt++
-	}
-	return
+	return Count(s)
 }
 
 func (s *Stream[T]) Reverse() *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			var data []T
-			for t := range s.data {
-				data = append(data, t)
-			}
-			for i := len(data) - 1; i >= 0; i-- {
-				ch <- data[i]
-			}
-		},
-	}
+	return derive(s, "Reverse", func(st *stage[T, T]) {
+		var data []T
+		st.each(func(t T) bool {
+			data = append(data, t)
+			return true
+		})
+		for i := len(data) - 1; i >= 0; i-- {
+			st.send(data[i])
+		}
+	})
 }
 
 func Map[T, R any](s *Stream[T], mapper MapFun[T, R]) *Stream[R] {
-	ch := make(chan R)
-	return &Stream[R]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				r := mapper(t)
-				ch <- r
-			}
-		},
+	mapped := fuse(s, "Map", func(t T) (R, bool) {
+		return mapper(t), true
+	})
+	mapped.opt.limit = func(n int) *Stream[R] {
+		return Map(s.Limit(n), mapper)
 	}
+	return mapped
 }
 
 func Filter[T any](s *Stream[T], filter FilterFun[T]) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				if filter(t) {
-					ch <- t
-				}
-			}
-		},
-	}
+	return s.Filter(filter)
 }
 
 func Limit[T any](s *Stream[T], i int) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			func() {
-				defer close(ch)
-				for t := range s.data {
-					if i > 0 {
-						ch <- t
-						i--
-					} else {
-						break
-					}
-				}
-			}()
-
-			for range s.data {
-			}
-
-		},
-	}
+	return s.Limit(i)
 }
 
 type SortOrder int
@@ -402,71 +250,91 @@ const (
 	DESC
 )
 
+func (o SortOrder) String() string {
+	if o == DESC {
+		return "DESC"
+	}
+	return "ASC"
+}
+
 func Sorted[T cmp.Ordered](s *Stream[T], order SortOrder) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			result := Collect(s)
-			sort.Slice(result, func(i, j int) bool {
-				if order == DESC {
-					return result[i] > result[j]
-				}
-				return result[i] < result[j]
-			})
-			for _, r := range result {
-				ch <- r
-			}
-		},
+	if sort := s.rewrite().sort; sort != nil {
+		return sort(order)
 	}
+	sorted := derive(s, "Sorted", func(st *stage[T, T]) {
+		var result []T
+		st.each(func(t T) bool {
+			result = append(result, t)
+			return true
+		})
+		sort.Slice(result, func(i, j int) bool {
+			if order == DESC {
+				return result[i] > result[j]
+			}
+			return result[i] < result[j]
+		})
+		for _, r := range result {
+			st.send(r)
+		}
+	}).withArgs(order)
+	sorted.opt = &rewrites[T]{sort: func(order SortOrder) *Stream[T] {
+		return Sorted(s, order)
+	}}
+	return sorted
 }
 
 func Reduce[T any, R any](s *Stream[T], result R, f func(ans R, i T) R) R {
-	s.Run()
-	for t := range s.data {
+	consume(s, "Reduce", func(t T) bool {
 		result = f(result, t)
-	}
+		return true
+	})
 	return result
 }
 
+// Scan passes downstream the intermediate results of folding the elements of s
+// with f, starting from init, such as the running totals of a sum. Unlike
+// Reduce it is lazy, and it emits one result per element, init excluded.
+func Scan[T, R any](s *Stream[T], init R, f func(acc R, t T) R) *Stream[R] {
+	return fold(s, "Scan", init, f)
+}
+
+func fold[T, R any](s *Stream[T], name string, init R, f func(acc R, t T) R) *Stream[R] {
+	scanned := derive(s, name, func(st *stage[T, R]) {
+		acc := init
+		st.each(func(t T) bool {
+			acc = f(acc, t)
+			st.send(acc)
+			return true
+		})
+	})
+	// the first n results only depend on the first n elements
+	scanned.opt = &rewrites[R]{limit: func(n int) *Stream[R] {
+		return fold(s.Limit(n), name, init, f)
+	}}
+	return scanned
+}
+
 func ForEach[T any](stream *Stream[T], f func(i T)) {
-	stream.Run()
-	for t := range stream.data {
-		f(t)
-	}
+	stream.ForEach(f)
 }
 
 // Distinct returns a new Stream with distinct elements from the input Stream.
 // Stateful Intermediate Operation.
 func Distinct[T comparable](s *Stream[T]) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			seen := make(map[T]struct{})
-			for t := range s.data {
-				if _, ok := seen[t]; !ok {
-					seen[t] = struct{}{}
-					ch <- t
-				}
+	return derive(s, "Distinct", func(st *stage[T, T]) {
+		seen := make(map[T]struct{})
+		st.each(func(t T) bool {
+			if _, ok := seen[t]; !ok {
+				seen[t] = struct{}{}
+				st.send(t)
 			}
-		},
-	}
+			return true
+		})
+	})
 }
 
 func AllMatch[T any](s *Stream[T], f func(T) bool) bool {
-	s.Run()
-	for t := range s.data {
-		if !f(t) {
-			go drain(s.data)
-			return false
-		}
-	}
-	return true
+	return s.AllMatch(f)
 }
 
 func NotAllMatch[T any](s *Stream[T], f func(T) bool) bool {
@@ -474,14 +342,7 @@ func NotAllMatch[T any](s *Stream[T], f func(T) bool) bool {
 }
 
 func AnyMatch[T any](s *Stream[T], f func(T) bool) bool {
-	s.Run()
-	for t := range s.data {
-		if f(t) {
-			go drain(s.data)
-			return true
-		}
-	}
-	return false
+	return s.AnyMatch(f)
 }
 
 func NoneMatch[T any](s *Stream[T], f func(T) bool) bool {
@@ -489,142 +350,65 @@ func NoneMatch[T any](s *Stream[T], f func(T) bool) bool {
 }
 
 func DropWhile[T any](s *Stream[T], f func(T) bool) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			dropping := true
-			for t := range s.data {
-				if dropping && f(t) {
-					continue
-				}
-				dropping = false
-				ch <- t
-			}
-		},
-	}
+	return s.DropWhile(f)
 }
 
 func TakeWhile[T any](s *Stream[T], f func(T) bool) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			func() {
-				defer close(ch)
-				for t := range s.data {
-					if f(t) {
-						ch <- t
-					} else {
-						break
-					}
-				}
-			}()
-			go drain(s.data)
-		},
-	}
+	return s.TakeWhile(f)
 }
 
 func Peek[T any](s *Stream[T], f func(T)) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				f(t)
-				ch <- t
-			}
-		},
-	}
+	return s.Peek(f)
 }
 
 func FindFirst[T any](s *Stream[T]) *T {
-	s.Run()
-	for t := range s.data {
-		go drain(s.data)
-		return &t
-	}
-	return nil
+	var first *T
+	consume(s, "FindFirst", func(t T) bool {
+		first = &t
+		return false
+	})
+	return first
 }
 
 func FlatMap[T, R any](s *Stream[T], f func(T) *Stream[R]) *Stream[R] {
-	ch := make(chan R)
-	return &Stream[R]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				is := f(t)
-				is.Run()
-				for r := range is.data {
-					ch <- r
+	return derive(s, "FlatMap", func(st *stage[T, R]) {
+		st.each(func(t T) bool {
+			is := f(t)
+			is.Run()
+			for r := range is.data {
+				if !st.send(r) {
+					stopStream(is.node, is.data)
+					return false
 				}
 			}
-		},
-	}
+			rethrow(is.node)
+			return true
+		})
+	})
 }
 
 func Min[T cmp.Ordered](s *Stream[T]) *T {
-	s.Run()
 	var minVal *T
-	for t := range s.data {
+	consume(s, "Min", func(t T) bool {
 		if minVal == nil || t < *minVal {
 			minVal = &t
 		}
-	}
+		return true
+	})
 	return minVal
 }
 
 func Max[T cmp.Ordered](s *Stream[T]) *T {
-	s.Run()
 	var maxVal *T
-	for t := range s.data {
+	consume(s, "Max", func(t T) bool {
 		if maxVal == nil || t > *maxVal {
 			maxVal = &t
 		}
-	}
+		return true
+	})
 	return maxVal
 }
 
 func Skip[T any](s *Stream[T], n int) *Stream[T] {
-	ch := make(chan T)
-	return &Stream[T]{
-		data: ch,
-		run: func() {
-			s.Run()
-			defer close(ch)
-			for t := range s.data {
-				if n > 0 {
-					n--
-					continue
-				}
-				ch <- t
-			}
-		},
-	}
-}
-
-func IfAllMatch[T any](s *Stream[T], f func(T) bool, action func(t T)) {
-	s.Run()
-	allMatch := true
-	var data []T
-	for t := range s.data {
-		if !f(t) {
-			allMatch = false
-			go drain(s.data)
-			break
-		}
-		data = append(data, t)
-	}
-	if allMatch {
-		for _, t := range data {
-			action(t)
-		}
-	}
+	return s.Skip(n)
 }
@@ -452,6 +452,36 @@ func TestMethodSortedDesc(t *testing.T) {
 	}
 }
 
+func TestOrderedStreamChain(t *testing.T) {
+	s := ToOrderedStream(New("pear", "fig", "apple", "fig", "kiwi")).
+		Distinct().
+		Filter(func(s string) bool {
+			return s != "kiwi"
+		}).
+		Sorted(DESC)
+	if largest := s.Max(); *largest != "pear" {
+		t.Errorf("Expected pear but got %v", *largest)
+	}
+
+	collected := ToOrderedStream(New("pear", "fig", "apple")).Sorted(ASC).Skip(1).ToComparableStream().CollectToSet()
+	if !reflect.DeepEqual(collected, map[string]struct{}{"fig": {}, "pear": {}}) {
+		t.Errorf("Expected [fig, pear] but got %v", collected)
+	}
+}
+
+func TestComparableStreamChain(t *testing.T) {
+	collected := ToComparableStream(New(1, 2, 2, 3, 3, 3)).
+		Map(func(i int) int {
+			return i * 10
+		}).
+		Distinct().
+		Limit(2).
+		Collect()
+	if !reflect.DeepEqual(collected, []int{10, 20}) {
+		t.Errorf("Expected [10, 20] but got %v", collected)
+	}
+}
+
 func TestMethodReduce(t *testing.T) {
 	reduced := New([]int{1, 2, 3, 4, 5}...).Reduce(0, func(ans, i int) int {
 		return ans + i
@@ -504,3 +534,21 @@ func TestMethodReverse(t *testing.T) {
 	collected := New([]int{1, 2, 3, 4, 5}...).Reverse().Collect()
 	assert.Equal(t, []int{5, 4, 3, 2, 1}, collected)
 }
+
+func TestScan(t *testing.T) {
+	maxes := Scan(New(3, 1, 4, 1, 5, 9, 2), 0, func(acc, i int) int {
+		return max(acc, i)
+	}).Collect()
+	assert.Equal(t, []int{3, 3, 4, 4, 5, 9, 9}, maxes)
+
+	var folded int
+	prefixes := Scan(New("a", "b", "c", "d"), "", func(acc, s string) string {
+		folded++
+		return acc + s
+	}).Limit(2)
+	assert.Equal(t, "#2 Scan\n"+
+		"└─ #1 Limit(2) [short-circuit]\n"+
+		"   └─ #0 New(4 elements)\n", prefixes.Explain())
+	assert.Equal(t, []string{"a", "ab"}, prefixes.Collect())
+	assert.Equal(t, 2, folded)
+}
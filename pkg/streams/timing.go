@@ -0,0 +1,186 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is reported by Timeout when the upstream stalls.
+var ErrTimeout = errors.New("streams: timed out waiting for element")
+
+// Rate is the throughput allowed by Throttle: Events elements per Per, with
+// bursts of up to Events elements.
+type Rate struct {
+	Events int
+	Per    time.Duration
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Events, r.Per)
+}
+
+// Throttle limits the rate at which elements are passed downstream using a
+// token bucket that holds at most rate.Events tokens. A non positive rate
+// disables throttling.
+func Throttle[T any](s *Stream[T], rate Rate, clock Clock) *Stream[T] {
+	return derive(s, "Throttle", func(st *stage[T, T]) {
+		if rate.Events <= 0 || rate.Per <= 0 {
+			st.each(func(t T) bool {
+				st.send(t)
+				return true
+			})
+			return
+		}
+		// tat is the theoretical arrival time of the next element, a bucket
+		// holding `rate.Events` tokens tolerates being `burst` ahead of it.
+		interval := rate.Per / time.Duration(rate.Events)
+		burst := interval * time.Duration(rate.Events-1)
+		var tat time.Time
+		st.each(func(t T) bool {
+			now := clock.Now()
+			if tat.Before(now) {
+				tat = now
+			}
+			if allowAt := tat.Add(-burst); now.Before(allowAt) {
+				clock.Sleep(allowAt.Sub(now))
+			}
+			tat = tat.Add(interval)
+			st.send(t)
+			return true
+		})
+	}).withArgs(rate)
+}
+
+// Debounce passes an element downstream only once no newer element arrived
+// for the quiet period. The last element is always passed on completion.
+func Debounce[T any](s *Stream[T], quiet time.Duration, clock Clock) *Stream[T] {
+	return derive(s, "Debounce", func(st *stage[T, T]) {
+		var pending T
+		var pendingAt time.Time
+		var hasPending bool
+		timer := clock.NewTimer()
+		defer timer.Stop()
+		// quietEnd is nil while no quiet period is being waited for
+		var quietEnd <-chan time.Time
+		for {
+			select {
+			case t, ok := <-st.in:
+				if !ok {
+					rethrow(st.from)
+					if hasPending {
+						st.send(pending)
+					}
+					return
+				}
+				st.received()
+				now := clock.Now()
+				if hasPending && now.Sub(pendingAt) >= quiet && !st.send(pending) {
+					st.stopInput()
+					return
+				}
+				pending, pendingAt, hasPending = t, now, true
+				timer.Reset(quiet)
+				quietEnd = timer.C()
+			case <-quietEnd:
+				if hasPending && !st.send(pending) {
+					st.stopInput()
+					return
+				}
+				hasPending = false
+				quietEnd = nil
+			case <-st.stop:
+				st.stopInput()
+				return
+			}
+		}
+	}).withArgs(quiet)
+}
+
+// Sample passes downstream the latest element seen in each interval, at the
+// end of that interval. The latest element is also passed on completion. It
+// panics if interval is not positive.
+func Sample[T any](s *Stream[T], interval time.Duration, clock Clock) *Stream[T] {
+	if interval <= 0 {
+		panic("streams: non-positive interval for Sample")
+	}
+	return derive(s, "Sample", func(st *stage[T, T]) {
+		var latest T
+		var hasLatest bool
+		next := clock.Now().Add(interval)
+		tick := clock.After(interval)
+		// flush reports whether the output is still read
+		flush := func(now time.Time) bool {
+			if hasLatest && !st.send(latest) {
+				return false
+			}
+			hasLatest = false
+			next = next.Add((now.Sub(next)/interval + 1) * interval)
+			tick = clock.After(next.Sub(now))
+			return true
+		}
+		for {
+			select {
+			case t, ok := <-st.in:
+				if !ok {
+					rethrow(st.from)
+					if hasLatest {
+						st.send(latest)
+					}
+					return
+				}
+				st.received()
+				if now := clock.Now(); !now.Before(next) && !flush(now) {
+					st.stopInput()
+					return
+				}
+				latest, hasLatest = t, true
+			case <-tick:
+				if !flush(clock.Now()) {
+					st.stopInput()
+					return
+				}
+			case <-st.stop:
+				st.stopInput()
+				return
+			}
+		}
+	}).withArgs(interval)
+}
+
+// Timeout ends the stream with ErrTimeout when the upstream does not produce
+// the next element within perElement.
+func Timeout[T any](s *Stream[T], perElement time.Duration, clock Clock) *ErrStream[T] {
+	errs := &errHolder{}
+	return toErrStream(derive(s, "Timeout", func(st *stage[T, T]) {
+		timer := clock.NewTimer()
+		defer timer.Stop()
+		for {
+			timer.Reset(perElement)
+			select {
+			case t, ok := <-st.in:
+				if !ok {
+					rethrow(st.from)
+					return
+				}
+				st.received()
+				if !st.send(t) {
+					st.stopInput()
+					return
+				}
+			case <-timer.C():
+				// a stream whose output is no longer read cannot time out
+				select {
+				case <-st.stop:
+				default:
+					errs.set(fmt.Errorf("%w after %s", ErrTimeout, perElement))
+				}
+				st.stopInput()
+				return
+			case <-st.stop:
+				st.stopInput()
+				return
+			}
+		}
+	}).withArgs(perElement), errs)
+}
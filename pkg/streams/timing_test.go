@@ -0,0 +1,225 @@
+package streams
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is a deterministic Clock. Now returns the scripted offsets one
+// call at a time (repeating the last one), Sleep advances time and records the
+// duration, and After and the timers fire immediately only when fire is set.
+type testClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	script []time.Duration
+	fire   bool
+	slept  []time.Duration
+	timers int
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.script) > 0 {
+		c.now = time.Time{}.Add(c.script[0])
+		c.script = c.script[1:]
+	}
+	return c.now
+}
+
+func (c *testClock) After(time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fire {
+		return nil
+	}
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *testClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept = append(c.slept, d)
+}
+
+func (c *testClock) NewTimer() Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timers++
+	return &testTimer{clock: c, c: make(chan time.Time, 1)}
+}
+
+type testTimer struct {
+	clock *testClock
+	c     chan time.Time
+}
+
+func (t *testTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *testTimer) Reset(time.Duration) {
+	t.Stop()
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	if t.clock.fire {
+		t.c <- t.clock.now
+	}
+}
+
+func (t *testTimer) Stop() {
+	select {
+	case <-t.c:
+	default:
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	clock := &testClock{}
+	collected := Collect(Throttle(New(1, 2, 3, 4, 5), Rate{Events: 2, Per: time.Second}, clock))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collected)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}, clock.slept)
+}
+
+func TestThrottleRefillsTokens(t *testing.T) {
+	clock := &testClock{script: []time.Duration{0, 0, 2 * time.Second, 2 * time.Second, 2 * time.Second}}
+	collected := Collect(Throttle(New(1, 2, 3, 4, 5), Rate{Events: 2, Per: time.Second}, clock))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collected)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond}, clock.slept)
+}
+
+func TestThrottleDisabled(t *testing.T) {
+	clock := &testClock{}
+	collected := Collect(Throttle(New(1, 2, 3), Rate{}, clock))
+	assert.Equal(t, []int{1, 2, 3}, collected)
+	assert.Empty(t, clock.slept)
+}
+
+func TestDebounce(t *testing.T) {
+	ms := time.Millisecond
+	clock := &testClock{script: []time.Duration{0, 10 * ms, 200 * ms, 210 * ms, 220 * ms, 500 * ms}}
+	collected := Collect(Debounce(New("a", "b", "c", "d", "e", "f"), 100*ms, clock))
+	assert.Equal(t, []string{"b", "e", "f"}, collected)
+	// the quiet period timer is reused across elements
+	assert.Equal(t, 1, clock.timers)
+}
+
+func TestDebounceEmpty(t *testing.T) {
+	collected := Collect(Debounce(New[int](), time.Second, &testClock{}))
+	assert.Empty(t, collected)
+}
+
+func TestSample(t *testing.T) {
+	ms := time.Millisecond
+	// the first scripted instant is the start of the first interval
+	clock := &testClock{script: []time.Duration{0, 10 * ms, 50 * ms, 120 * ms, 130 * ms, 450 * ms}}
+	collected := Collect(Sample(New(1, 2, 3, 4, 5), 100*ms, clock))
+	assert.Equal(t, []int{2, 4, 5}, collected)
+}
+
+func TestSampleNonPositiveInterval(t *testing.T) {
+	assert.PanicsWithValue(t, "streams: non-positive interval for Sample", func() {
+		Sample(New(1), 0, &testClock{})
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	clock := &testClock{}
+	s := Timeout(New(1, 2, 3), time.Second, clock)
+	collected, err := s.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, collected)
+	// the timer is reused across elements
+	assert.Equal(t, 1, clock.timers)
+}
+
+func TestSystemTimer(t *testing.T) {
+	timer := SystemClock.NewTimer()
+	timer.Reset(time.Millisecond)
+	<-timer.C()
+	// a timer that fired and was not received from is reset too
+	timer.Reset(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	timer.Reset(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("stale tick")
+	case <-time.After(10 * time.Millisecond):
+	}
+	timer.Stop()
+}
+
+func TestTimeoutStalledUpstream(t *testing.T) {
+	stalled := make(chan int)
+	defer close(stalled)
+	s := Timeout(&Stream[int]{data: stalled, run: func() {}}, time.Second, &testClock{fire: true})
+	collected, err := s.Collect()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Empty(t, collected)
+}
+
+func TestTimeoutChained(t *testing.T) {
+	s := Timeout(New(1, 2, 3, 4), time.Second, &testClock{}).
+		Filter(func(i int) bool {
+			return i%2 == 0
+		})
+	collected, err := MapErrStream(s, func(i int) string {
+		return string(rune('a' + i))
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "e"}, collected)
+}
+
+// counted returns a stream of n elements counting in read those read from it.
+func counted(n int, read *atomic.Int64) *Stream[int] {
+	data := make([]int, n)
+	for i := range data {
+		data[i] = i
+	}
+	return New(data...).Peek(func(int) {
+		read.Add(1)
+	})
+}
+
+// ticking returns a clock whose Now moves by step on every call.
+func ticking(n int, step time.Duration) *testClock {
+	script := make([]time.Duration, n)
+	for i := range script {
+		script[i] = time.Duration(i) * step
+	}
+	return &testClock{script: script}
+}
+
+// assertStopped checks that the stream counted by read was not read to the
+// end, once the stages had time to notice they were stopped.
+func assertStopped(t *testing.T, read *atomic.Int64, n int) {
+	t.Helper()
+	time.Sleep(20 * time.Millisecond)
+	assert.Less(t, read.Load(), int64(n))
+}
+
+func TestTimingShortCircuit(t *testing.T) {
+	const n = 1000
+	var read atomic.Int64
+	collected, err := Timeout(counted(n, &read), time.Second, &testClock{}).Limit(1).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0}, collected)
+	assertStopped(t, &read, n)
+
+	read.Store(0)
+	debounced := Debounce(counted(n, &read), time.Millisecond, ticking(n+1, time.Second))
+	assert.Equal(t, []int{0}, debounced.Limit(1).Collect())
+	assertStopped(t, &read, n)
+
+	read.Store(0)
+	sampled := Sample(counted(n, &read), time.Second, ticking(n+1, time.Second))
+	assert.Equal(t, []int{0}, sampled.Limit(1).Collect())
+	assertStopped(t, &read, n)
+}
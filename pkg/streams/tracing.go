@@ -0,0 +1,22 @@
+package streams
+
+// Tracer opens a span for every stage of a traced pipeline, sources,
+// intermediate operations and terminal operations alike. StartStage is called
+// from the stage goroutine when the stage starts running.
+type Tracer interface {
+	StartStage(id int, name string) StageSpan
+}
+
+// StageSpan is closed with the measurements of the stage, including its
+// element counts, once the stage is done.
+type StageSpan interface {
+	End(stats StageStats)
+}
+
+// WithTracer traces every stage of the pipeline s belongs to, the ones before
+// s as well as the ones chained after it. It must be called before the stream
+// runs.
+func (s *Stream[T]) WithTracer(t Tracer) *Stream[T] {
+	s.pipeline().setTracer(t)
+	return s
+}
@@ -8,26 +8,35 @@ func drain[T any](ch <-chan T) {
 }
 
 func Collect[T any](s *Stream[T]) []T {
-	return Reduce(s, []T{}, func(ans []T, i T) []T {
-		return append(ans, i)
+	result := []T{}
+	consume(s, "Collect", func(t T) bool {
+		result = append(result, t)
+		return true
 	})
+	return result
 }
 
-func Count[T any](s *Stream[T]) int64 {
-	return Reduce(s, int64(0), func(ans int64, i T) int64 {
-		return ans + 1
+func Count[T any](s *Stream[T]) (cnt int64) {
+	consume(s, "Count", func(T) bool {
+		cnt++
+		return true
 	})
+	return cnt
 }
 
-func Sum[T constraints.Integer | constraints.Float](s *Stream[T]) T {
-	return Reduce(s, 0, func(ans T, i T) T {
-		return ans + i
+func Sum[T constraints.Integer | constraints.Float](s *Stream[T]) (sum T) {
+	consume(s, "Sum", func(t T) bool {
+		sum += t
+		return true
 	})
+	return sum
 }
 
 func CollectToSet[T comparable](stream *Stream[T]) map[T]struct{} {
-	return Reduce(stream, map[T]struct{}{}, func(ans map[T]struct{}, i T) map[T]struct{} {
-		ans[i] = struct{}{}
-		return ans
+	result := map[T]struct{}{}
+	consume(stream, "CollectToSet", func(t T) bool {
+		result[t] = struct{}{}
+		return true
 	})
+	return result
 }
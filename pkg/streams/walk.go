@@ -0,0 +1,96 @@
+package streams
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// FileEntry is an entry found by WalkDir. Path is relative to the walked file
+// system and Depth is 0 for the root.
+type FileEntry struct {
+	fs.DirEntry
+	Path  string
+	Depth int
+}
+
+// WalkOptions configures WalkDir.
+type WalkOptions struct {
+	// MaxDepth stops the walk below the given depth, 0 means no limit.
+	MaxDepth int
+	// SkipSymlinks leaves symbolic links out. They are reported but never
+	// followed otherwise, like fs.WalkDir does.
+	SkipSymlinks bool
+	// Include keeps only the entries matching one of the path.Match patterns.
+	// Directories are walked regardless.
+	Include []string
+	// Exclude drops the entries matching one of the patterns, excluded
+	// directories are not walked.
+	Exclude []string
+}
+
+// globMatch matches patterns containing a slash against the whole path and the
+// others against the base name.
+func globMatch(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		name := path.Base(p)
+		if strings.Contains(pattern, "/") {
+			name = p
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func depthOf(root, p string) int {
+	if p == root {
+		return 0
+	}
+	rel := p
+	if root != "." {
+		rel = strings.TrimPrefix(p, root+"/")
+	}
+	return strings.Count(rel, "/") + 1
+}
+
+// WalkDir streams the entries of fsys under root in lexical order, as
+// fs.WalkDir visits them. The first walk error ends the stream and is reported
+// by the error aware terminals.
+func WalkDir(fsys fs.FS, root string, opts ...WalkOptions) *ErrStream[FileEntry] {
+	var o WalkOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	errs := &errHolder{}
+	return toErrStream(source("WalkDir", func(st *stage[FileEntry, FileEntry]) {
+		err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if p != root && globMatch(o.Exclude, p) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if o.SkipSymlinks && d.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+			depth := depthOf(root, p)
+			if len(o.Include) == 0 || globMatch(o.Include, p) {
+				if !st.send(FileEntry{DirEntry: d, Path: p, Depth: depth}) {
+					return fs.SkipAll
+				}
+			}
+			if d.IsDir() && o.MaxDepth > 0 && depth >= o.MaxDepth {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			errs.set(err)
+		}
+	}).withArgs(root), errs)
+}
@@ -0,0 +1,74 @@
+package streams
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md":           {Data: []byte("readme")},
+		"cmd/main.go":         {Data: []byte("package main")},
+		"pkg/a/a.go":          {Data: []byte("package a")},
+		"pkg/a/a_test.go":     {Data: []byte("package a")},
+		"pkg/a/deep/deep.go":  {Data: []byte("package deep")},
+		"vendor/lib/lib.go":   {Data: []byte("package lib")},
+		"pkg/link":            {Data: []byte("a"), Mode: fs.ModeSymlink},
+		"pkg/a/testdata/x.go": {Data: []byte("package x")},
+	}
+}
+
+func entryPaths(entries []FileEntry) []string {
+	return Collect(Map(New(entries...), func(e FileEntry) string {
+		return e.Path
+	}))
+}
+
+func TestWalkDir(t *testing.T) {
+	entries, err := WalkDir(testFS(), ".").
+		Filter(func(e FileEntry) bool {
+			return !e.IsDir()
+		}).
+		Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"README.md",
+		"cmd/main.go",
+		"pkg/a/a.go",
+		"pkg/a/a_test.go",
+		"pkg/a/deep/deep.go",
+		"pkg/a/testdata/x.go",
+		"pkg/link",
+		"vendor/lib/lib.go",
+	}, entryPaths(entries))
+}
+
+func TestWalkDirOptions(t *testing.T) {
+	entries, err := WalkDir(testFS(), "pkg", WalkOptions{
+		MaxDepth:     2,
+		SkipSymlinks: true,
+		Include:      []string{"*.go"},
+		Exclude:      []string{"*_test.go", "testdata"},
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pkg/a/a.go"}, entryPaths(entries))
+	assert.Equal(t, 2, entries[0].Depth)
+}
+
+func TestWalkDirPathPattern(t *testing.T) {
+	entries, err := WalkDir(testFS(), ".", WalkOptions{
+		Include: []string{"*.go"},
+		Exclude: []string{"vendor/*", "pkg/a/*"},
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cmd/main.go"}, entryPaths(entries))
+}
+
+func TestWalkDirMissingRoot(t *testing.T) {
+	entries, err := WalkDir(testFS(), "missing").Collect()
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,222 @@
+package streams
+
+import (
+	"sort"
+	"time"
+)
+
+// Window is a group of elements whose timestamps fall in [Start, End).
+// Elements are ordered by timestamp.
+type Window[T any] struct {
+	Start    time.Time
+	End      time.Time
+	Elements []T
+}
+
+// WindowOptions controls how windowing operators deal with out of order
+// elements.
+//
+// The watermark is the greatest timestamp seen so far minus AllowedLateness. A
+// window is emitted once the watermark passes its end, and elements that only
+// belong to windows already emitted are late: they are dropped and handed to
+// OnLate when it is set.
+type WindowOptions[T any] struct {
+	AllowedLateness time.Duration
+	OnLate          func(T)
+}
+
+type timed[T any] struct {
+	ts time.Time
+	v  T
+}
+
+type openWindow[T any] struct {
+	start, end time.Time
+	elements   []timed[T]
+}
+
+func (w *openWindow[T]) toWindow() Window[T] {
+	sort.SliceStable(w.elements, func(i, j int) bool {
+		return w.elements[i].ts.Before(w.elements[j].ts)
+	})
+	elements := make([]T, len(w.elements))
+	for i, e := range w.elements {
+		elements[i] = e.v
+	}
+	return Window[T]{Start: w.start, End: w.end, Elements: elements}
+}
+
+type watermark[T any] struct {
+	opts  WindowOptions[T]
+	maxTs time.Time
+	seen  bool
+}
+
+func newWatermark[T any](opts []WindowOptions[T]) *watermark[T] {
+	w := &watermark[T]{}
+	if len(opts) > 0 {
+		w.opts = opts[0]
+	}
+	return w
+}
+
+func (w *watermark[T]) observe(ts time.Time) {
+	if !w.seen || ts.After(w.maxTs) {
+		w.maxTs = ts
+		w.seen = true
+	}
+}
+
+func (w *watermark[T]) passed(end time.Time) bool {
+	return w.seen && !w.maxTs.Add(-w.opts.AllowedLateness).Before(end)
+}
+
+func (w *watermark[T]) late(v T) {
+	if w.opts.OnLate != nil {
+		w.opts.OnLate(v)
+	}
+}
+
+// emitWindows sends the windows accepted by done in (start, end) order and
+// removes them from open.
+func emitWindows[T any](st *stage[T, Window[T]], open []*openWindow[T], done func(*openWindow[T]) bool) []*openWindow[T] {
+	sort.Slice(open, func(i, j int) bool {
+		if open[i].start.Equal(open[j].start) {
+			return open[i].end.Before(open[j].end)
+		}
+		return open[i].start.Before(open[j].start)
+	})
+	remaining := open[:0]
+	for _, w := range open {
+		if done(w) {
+			st.send(w.toWindow())
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	return remaining
+}
+
+func fixedWindows[T any](s *Stream[T], name string, size time.Duration, starts func(time.Time) []time.Time, tsFn func(T) time.Time, opts []WindowOptions[T]) *Stream[Window[T]] {
+	return derive(s, name, func(st *stage[T, Window[T]]) {
+		wm := newWatermark(opts)
+		var open []*openWindow[T]
+		byStart := make(map[time.Time]*openWindow[T])
+		st.each(func(t T) bool {
+			ts := tsFn(t)
+			wm.observe(ts)
+			candidates := starts(ts)
+			assigned := false
+			for _, start := range candidates {
+				end := start.Add(size)
+				if wm.passed(end) {
+					continue
+				}
+				w, ok := byStart[start]
+				if !ok {
+					w = &openWindow[T]{start: start, end: end}
+					byStart[start] = w
+					open = append(open, w)
+				}
+				w.elements = append(w.elements, timed[T]{ts, t})
+				assigned = true
+			}
+			if !assigned && len(candidates) > 0 {
+				wm.late(t)
+			}
+			open = emitWindows(st, open, func(w *openWindow[T]) bool {
+				if wm.passed(w.end) {
+					delete(byStart, w.start)
+					return true
+				}
+				return false
+			})
+			return true
+		})
+		emitWindows(st, open, func(*openWindow[T]) bool {
+			return true
+		})
+	})
+}
+
+// TumblingWindow groups elements into consecutive, non overlapping windows of
+// the given size based on the event time returned by tsFn.
+// It panics if size is not positive.
+// Stateful Intermediate Operation.
+func TumblingWindow[T any](s *Stream[T], size time.Duration, tsFn func(T) time.Time, opts ...WindowOptions[T]) *Stream[Window[T]] {
+	if size <= 0 {
+		panic("streams: non-positive size for TumblingWindow")
+	}
+	return fixedWindows(s, "TumblingWindow", size, func(ts time.Time) []time.Time {
+		return []time.Time{ts.Truncate(size)}
+	}, tsFn, opts).withArgs(size)
+}
+
+// SlidingWindow groups elements into windows of the given size starting every
+// slide, so an element belongs to every window covering its event time.
+// It panics if size or slide is not positive.
+// Stateful Intermediate Operation.
+func SlidingWindow[T any](s *Stream[T], size, slide time.Duration, tsFn func(T) time.Time, opts ...WindowOptions[T]) *Stream[Window[T]] {
+	if size <= 0 {
+		panic("streams: non-positive size for SlidingWindow")
+	}
+	if slide <= 0 {
+		panic("streams: non-positive slide for SlidingWindow")
+	}
+	return fixedWindows(s, "SlidingWindow", size, func(ts time.Time) []time.Time {
+		var starts []time.Time
+		for start := ts.Truncate(slide); start.After(ts.Add(-size)); start = start.Add(-slide) {
+			starts = append(starts, start)
+		}
+		return starts
+	}, tsFn, opts).withArgs(size, slide)
+}
+
+// SessionWindow groups elements into sessions that end once no element arrived
+// for the gap. A session spans from its first event time to its last event
+// time plus the gap.
+// It panics if gap is not positive.
+// Stateful Intermediate Operation.
+func SessionWindow[T any](s *Stream[T], gap time.Duration, tsFn func(T) time.Time, opts ...WindowOptions[T]) *Stream[Window[T]] {
+	if gap <= 0 {
+		panic("streams: non-positive gap for SessionWindow")
+	}
+	return derive(s, "SessionWindow", func(st *stage[T, Window[T]]) {
+		wm := newWatermark(opts)
+		var open []*openWindow[T]
+		st.each(func(t T) bool {
+			ts := tsFn(t)
+			wm.observe(ts)
+			session := &openWindow[T]{start: ts, end: ts.Add(gap), elements: []timed[T]{{ts, t}}}
+			merged := false
+			remaining := open[:0]
+			for _, w := range open {
+				if w.start.After(session.end) || session.start.After(w.end) {
+					remaining = append(remaining, w)
+					continue
+				}
+				if w.start.Before(session.start) {
+					session.start = w.start
+				}
+				if w.end.After(session.end) {
+					session.end = w.end
+				}
+				session.elements = append(w.elements, session.elements...)
+				merged = true
+			}
+			open = remaining
+			if !merged && wm.passed(session.end) {
+				wm.late(t)
+			} else {
+				open = append(open, session)
+			}
+			open = emitWindows(st, open, func(w *openWindow[T]) bool {
+				return wm.passed(w.end)
+			})
+			return true
+		})
+		emitWindows(st, open, func(*openWindow[T]) bool {
+			return true
+		})
+	}).withArgs(gap)
+}
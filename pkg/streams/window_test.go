@@ -0,0 +1,117 @@
+package streams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type event struct {
+	name string
+	at   time.Duration
+}
+
+func eventTime(e event) time.Time {
+	return time.Time{}.Add(e.at)
+}
+
+func windowNames(windows []Window[event]) [][]string {
+	var names [][]string
+	for _, w := range windows {
+		var group []string
+		for _, e := range w.Elements {
+			group = append(group, e.name)
+		}
+		names = append(names, group)
+	}
+	return names
+}
+
+func TestTumblingWindow(t *testing.T) {
+	s := New(
+		event{"a", 1 * time.Second},
+		event{"b", 4 * time.Second},
+		event{"c", 6 * time.Second},
+		event{"d", 14 * time.Second},
+	)
+	windows := Collect(TumblingWindow(s, 5*time.Second, eventTime))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {"d"}}, windowNames(windows))
+	assert.Equal(t, eventTime(event{at: 5 * time.Second}), windows[1].Start)
+	assert.Equal(t, eventTime(event{at: 10 * time.Second}), windows[1].End)
+}
+
+func TestTumblingWindowOutOfOrder(t *testing.T) {
+	s := New(
+		event{"a", 1 * time.Second},
+		event{"b", 6 * time.Second},
+		event{"c", 4 * time.Second},
+		event{"d", 12 * time.Second},
+		event{"late", 3 * time.Second},
+	)
+	var late []string
+	windows := Collect(TumblingWindow(s, 5*time.Second, eventTime, WindowOptions[event]{
+		AllowedLateness: 2 * time.Second,
+		OnLate: func(e event) {
+			late = append(late, e.name)
+		},
+	}))
+	assert.Equal(t, [][]string{{"a", "c"}, {"b"}, {"d"}}, windowNames(windows))
+	assert.Equal(t, []string{"late"}, late)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	s := New(
+		event{"a", 1 * time.Second},
+		event{"b", 3 * time.Second},
+		event{"c", 5 * time.Second},
+	)
+	windows := Collect(SlidingWindow(s, 4*time.Second, 2*time.Second, eventTime))
+	assert.Equal(t, [][]string{{"a"}, {"a", "b"}, {"b", "c"}, {"c"}}, windowNames(windows))
+	assert.Equal(t, eventTime(event{at: -2 * time.Second}), windows[0].Start)
+}
+
+func TestWindowNonPositiveDuration(t *testing.T) {
+	s := New(event{"a", time.Second})
+	assert.PanicsWithValue(t, "streams: non-positive size for TumblingWindow", func() {
+		TumblingWindow(s, 0, eventTime)
+	})
+	assert.PanicsWithValue(t, "streams: non-positive size for SlidingWindow", func() {
+		SlidingWindow(s, -time.Second, time.Second, eventTime)
+	})
+	assert.PanicsWithValue(t, "streams: non-positive slide for SlidingWindow", func() {
+		SlidingWindow(s, time.Second, 0, eventTime)
+	})
+	assert.PanicsWithValue(t, "streams: non-positive gap for SessionWindow", func() {
+		SessionWindow(s, 0, eventTime)
+	})
+}
+
+func TestSessionWindow(t *testing.T) {
+	s := New(
+		event{"a", 1 * time.Second},
+		event{"b", 2 * time.Second},
+		event{"c", 10 * time.Second},
+		event{"d", 11 * time.Second},
+		event{"e", 30 * time.Second},
+	)
+	windows := Collect(SessionWindow(s, 3*time.Second, eventTime))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, windowNames(windows))
+	assert.Equal(t, eventTime(event{at: 10 * time.Second}), windows[1].Start)
+	assert.Equal(t, eventTime(event{at: 14 * time.Second}), windows[1].End)
+}
+
+func TestSessionWindowMergesOutOfOrder(t *testing.T) {
+	s := New(
+		event{"a", 1 * time.Second},
+		event{"c", 6 * time.Second},
+		event{"b", 4 * time.Second},
+	)
+	windows := Collect(SessionWindow(s, 3*time.Second, eventTime, WindowOptions[event]{AllowedLateness: 5 * time.Second}))
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, windowNames(windows))
+}
+
+func TestWindowEmpty(t *testing.T) {
+	assert.Empty(t, Collect(TumblingWindow(New[event](), time.Second, eventTime)))
+	assert.Empty(t, Collect(SessionWindow(New[event](), time.Second, eventTime)))
+}